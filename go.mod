@@ -4,5 +4,9 @@ go 1.15
 
 require (
 	github.com/alphahorizonio/unisockets v0.1.1
+	github.com/prometheus/client_golang v1.11.0
 	github.com/valyala/fastjson v1.6.3
+	golang.org/x/crypto v0.0.0-20210817164053-32db794688a5
+	golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1
+	google.golang.org/grpc v1.40.0
 )
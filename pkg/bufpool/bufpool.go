@@ -0,0 +1,87 @@
+package bufpool
+
+import (
+	"math/bits"
+	"net"
+	"sync"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+const (
+	minClass = 6  // 64 bytes
+	maxClass = 24 // 16 MiB
+)
+
+var (
+	DefaultPool = NewPool()
+)
+
+type Pool struct {
+	// One pool per power of two, so that buffers are re-used for similar sizes
+	classes [maxClass + 1]sync.Pool
+}
+
+func NewPool() *Pool {
+	return &Pool{}
+}
+
+func classOf(size int) int {
+	class := bits.Len(uint(size - 1))
+	if class < minClass {
+		class = minClass
+	}
+
+	return class
+}
+
+func (p *Pool) Get(size int) []byte {
+	if size <= 0 {
+		return []byte{}
+	}
+
+	class := classOf(size)
+	if class > maxClass {
+		return make([]byte, size)
+	}
+
+	if buf, ok := p.classes[class].Get().(*[]byte); ok {
+		return (*buf)[:size]
+	}
+
+	return make([]byte, size, 1<<class)
+}
+
+func (p *Pool) Put(buf []byte) {
+	// Buffers are filed under the largest class they can fully serve
+	class := bits.Len(uint(cap(buf))) - 1
+	if class < minClass || class > maxClass {
+		return
+	}
+
+	buf = buf[:cap(buf)]
+	p.classes[class].Put(&buf)
+}
+
+func (p *Pool) ReadFull(conn net.Conn, size int, handler func([]byte) error) error {
+	buf := p.Get(size)
+	defer p.Put(buf)
+
+	if _, err := tinynet.ReadFull(conn, buf); err != nil {
+		return err
+	}
+
+	return handler(buf)
+}
+
+func Get(size int) []byte {
+	return DefaultPool.Get(size)
+}
+
+func Put(buf []byte) {
+	DefaultPool.Put(buf)
+}
+
+func ReadFull(conn net.Conn, size int, handler func([]byte) error) error {
+	return DefaultPool.ReadFull(conn, size, handler)
+}
@@ -0,0 +1,179 @@
+package capture
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+const (
+	tcpFlagPSH = 0x08
+	tcpFlagACK = 0x10
+
+	maxSegmentSize = 1<<16 - 1 - 60 // Leave space for the IPv6 and TCP headers
+)
+
+type endpoint struct {
+	ip   net.IP
+	port int
+}
+
+func toEndpoint(addr net.Addr, fallback net.IP) endpoint {
+	switch a := addr.(type) {
+	case *tinynet.TCPAddr:
+		if a != nil {
+			return endpoint{net.IP(a.IP), a.Port}
+		}
+	case *net.TCPAddr:
+		if a != nil {
+			return endpoint{a.IP, a.Port}
+		}
+	}
+
+	// Use placeholder addresses for pipes and other non-IP connections
+	return endpoint{fallback, 0}
+}
+
+type captureConn struct {
+	net.Conn
+
+	writer *PcapngWriter
+
+	local  endpoint
+	remote endpoint
+
+	// Sequence numbers of each direction, so that TCP streams can be reassembled
+	localSeq  uint32
+	remoteSeq uint32
+	lock      sync.Mutex
+}
+
+func CaptureConn(inner net.Conn, writer *PcapngWriter) net.Conn {
+	return &captureConn{
+		Conn:   inner,
+		writer: writer,
+
+		local:  toEndpoint(inner.LocalAddr(), net.IPv4(127, 0, 0, 1)),
+		remote: toEndpoint(inner.RemoteAddr(), net.IPv4(127, 0, 0, 2)),
+
+		localSeq:  1,
+		remoteSeq: 1,
+	}
+}
+
+func (c *captureConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.capture(b[:n], false)
+	}
+
+	return n, err
+}
+
+func (c *captureConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.capture(b[:n], true)
+	}
+
+	return n, err
+}
+
+func (c *captureConn) capture(payload []byte, outgoing bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	for len(payload) > 0 {
+		chunk := payload
+		if len(chunk) > maxSegmentSize {
+			chunk = chunk[:maxSegmentSize]
+		}
+
+		var packet []byte
+		if outgoing {
+			packet = buildPacket(c.local, c.remote, c.localSeq, c.remoteSeq, chunk)
+			c.localSeq += uint32(len(chunk))
+		} else {
+			packet = buildPacket(c.remote, c.local, c.remoteSeq, c.localSeq, chunk)
+			c.remoteSeq += uint32(len(chunk))
+		}
+
+		// Capture errors must not interrupt the connection; the writer keeps the first one for Err
+		_ = c.writer.WritePacket(now, packet)
+
+		payload = payload[len(chunk):]
+	}
+}
+
+func checksum(data []byte) uint16 {
+	sum := uint32(0)
+	for i := 0; i+1 < len(data); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(data[i:]))
+	}
+
+	if len(data)%2 == 1 {
+		sum += uint32(data[len(data)-1]) << 8
+	}
+
+	for sum > 0xffff {
+		sum = (sum >> 16) + (sum & 0xffff)
+	}
+
+	return ^uint16(sum)
+}
+
+func buildPacket(src, dst endpoint, seq, ack uint32, payload []byte) []byte {
+	// TCP header
+	tcp := make([]byte, 20+len(payload))
+	binary.BigEndian.PutUint16(tcp[0:2], uint16(src.port))
+	binary.BigEndian.PutUint16(tcp[2:4], uint16(dst.port))
+	binary.BigEndian.PutUint32(tcp[4:8], seq)
+	binary.BigEndian.PutUint32(tcp[8:12], ack)
+	tcp[12] = 5 << 4 // Data offset
+	tcp[13] = tcpFlagPSH | tcpFlagACK
+	binary.BigEndian.PutUint16(tcp[14:16], 0xffff) // Window
+	copy(tcp[20:], payload)
+
+	src4, dst4 := src.ip.To4(), dst.ip.To4()
+	if src4 != nil && dst4 != nil {
+		// IPv4 header
+		ip := make([]byte, 20)
+		ip[0] = 0x45 // Version 4, header length 5
+		binary.BigEndian.PutUint16(ip[2:4], uint16(len(ip)+len(tcp)))
+		ip[8] = 64 // TTL
+		ip[9] = 6  // TCP
+		copy(ip[12:16], src4)
+		copy(ip[16:20], dst4)
+		binary.BigEndian.PutUint16(ip[10:12], checksum(ip))
+
+		pseudo := make([]byte, 12)
+		copy(pseudo[0:4], src4)
+		copy(pseudo[4:8], dst4)
+		pseudo[9] = 6
+		binary.BigEndian.PutUint16(pseudo[10:12], uint16(len(tcp)))
+		binary.BigEndian.PutUint16(tcp[16:18], checksum(append(pseudo, tcp...)))
+
+		return append(ip, tcp...)
+	}
+
+	// IPv6 header
+	ip := make([]byte, 40)
+	ip[0] = 0x60 // Version 6
+	binary.BigEndian.PutUint16(ip[4:6], uint16(len(tcp)))
+	ip[6] = 6  // TCP
+	ip[7] = 64 // Hop limit
+	copy(ip[8:24], src.ip.To16())
+	copy(ip[24:40], dst.ip.To16())
+
+	pseudo := make([]byte, 40)
+	copy(pseudo[0:32], ip[8:40])
+	binary.BigEndian.PutUint32(pseudo[32:36], uint32(len(tcp)))
+	pseudo[39] = 6
+	binary.BigEndian.PutUint16(tcp[16:18], checksum(append(pseudo, tcp...)))
+
+	return append(ip, tcp...)
+}
@@ -0,0 +1,98 @@
+package capture
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	blockTypeSHB = 0x0a0d0d0a
+	blockTypeIDB = 0x00000001
+	blockTypeEPB = 0x00000006
+
+	byteOrderMagic = 0x1a2b3c4d
+
+	linkTypeRaw = 101 // Raw IPv4/IPv6 packets without a link-layer header
+)
+
+type PcapngWriter struct {
+	w    io.Writer
+	err  error
+	lock sync.Mutex
+}
+
+func NewPcapngWriter(w io.Writer) (*PcapngWriter, error) {
+	pw := &PcapngWriter{
+		w: w,
+	}
+
+	// Section header block
+	shb := make([]byte, 16)
+	binary.LittleEndian.PutUint32(shb[0:4], byteOrderMagic)
+	binary.LittleEndian.PutUint16(shb[4:6], 1) // Major version
+	binary.LittleEndian.PutUint16(shb[6:8], 0) // Minor version
+	binary.LittleEndian.PutUint64(shb[8:16], ^uint64(0))
+
+	if err := pw.writeBlock(blockTypeSHB, shb); err != nil {
+		return nil, err
+	}
+
+	// Interface description block
+	idb := make([]byte, 8)
+	binary.LittleEndian.PutUint16(idb[0:2], linkTypeRaw)
+	binary.LittleEndian.PutUint32(idb[4:8], 0) // No snapshot length limit
+
+	if err := pw.writeBlock(blockTypeIDB, idb); err != nil {
+		return nil, err
+	}
+
+	return pw, nil
+}
+
+func (pw *PcapngWriter) writeBlock(blockType uint32, body []byte) error {
+	padded := (len(body) + 3) &^ 3
+	total := 12 + padded
+
+	block := make([]byte, total)
+	binary.LittleEndian.PutUint32(block[0:4], blockType)
+	binary.LittleEndian.PutUint32(block[4:8], uint32(total))
+	copy(block[8:], body)
+	binary.LittleEndian.PutUint32(block[total-4:], uint32(total))
+
+	pw.lock.Lock()
+	defer pw.lock.Unlock()
+
+	// The capture would be corrupt after a partial block, so stop at the first error
+	if pw.err != nil {
+		return pw.err
+	}
+
+	_, pw.err = pw.w.Write(block)
+
+	return pw.err
+}
+
+// Returns the first error which occurred while writing
+func (pw *PcapngWriter) Err() error {
+	pw.lock.Lock()
+	defer pw.lock.Unlock()
+
+	return pw.err
+}
+
+func (pw *PcapngWriter) WritePacket(t time.Time, packet []byte) error {
+	// Timestamps use the default resolution of microseconds
+	ts := uint64(t.UnixNano() / int64(time.Microsecond))
+
+	epb := make([]byte, 20+len(packet))
+	binary.LittleEndian.PutUint32(epb[0:4], 0) // Interface ID
+	binary.LittleEndian.PutUint32(epb[4:8], uint32(ts>>32))
+	binary.LittleEndian.PutUint32(epb[8:12], uint32(ts))
+	binary.LittleEndian.PutUint32(epb[12:16], uint32(len(packet)))
+	binary.LittleEndian.PutUint32(epb[16:20], uint32(len(packet)))
+	copy(epb[20:], packet)
+
+	return pw.writeBlock(blockTypeEPB, epb)
+}
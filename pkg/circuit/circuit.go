@@ -0,0 +1,205 @@
+package circuit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+const (
+	DefaultFailureThreshold = 5
+	DefaultSuccessThreshold = 1
+	DefaultTimeout          = 30 * time.Second
+)
+
+var (
+	ErrOpen = errors.New("could not dial: circuit breaker is open")
+)
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateHalfOpen
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half-open"
+	case StateOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+type BreakerOptions struct {
+	// Consecutive failures after which the breaker opens
+	FailureThreshold int
+	// Consecutive successes in the half-open state after which the breaker closes
+	SuccessThreshold int
+	// Time the breaker stays open before letting a dial through again
+	Timeout time.Duration
+}
+
+type Breaker struct {
+	dialer tinynet.ContextDialer
+	opts   BreakerOptions
+
+	state     State
+	failures  int
+	successes int
+	openedAt  time.Time
+	listeners []func(from, to State)
+	lock      sync.Mutex
+}
+
+func NewBreaker(dialer tinynet.ContextDialer, opts BreakerOptions) *Breaker {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = DefaultFailureThreshold
+	}
+
+	if opts.SuccessThreshold <= 0 {
+		opts.SuccessThreshold = DefaultSuccessThreshold
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+
+	return &Breaker{
+		dialer: dialer,
+		opts:   opts,
+		state:  StateClosed,
+	}
+}
+
+func (b *Breaker) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	b.lock.Lock()
+	state, transition := b.currentState()
+	b.lock.Unlock()
+
+	b.notify(transition)
+
+	if state == StateOpen {
+		return nil, ErrOpen
+	}
+
+	conn, err := b.dialer.Dial(ctx, network, address)
+
+	// Cancellation by the caller says nothing about the backend's health
+	if err != nil && ctx.Err() != nil {
+		return nil, err
+	}
+
+	b.lock.Lock()
+	if err != nil {
+		transition = b.onFailure()
+	} else {
+		transition = b.onSuccess()
+	}
+	b.lock.Unlock()
+
+	b.notify(transition)
+
+	return conn, err
+}
+
+func (b *Breaker) State() State {
+	b.lock.Lock()
+	state, transition := b.currentState()
+	b.lock.Unlock()
+
+	b.notify(transition)
+
+	return state
+}
+
+func (b *Breaker) OnStateChange(listener func(from, to State)) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.listeners = append(b.listeners, listener)
+}
+
+type transition struct {
+	from      State
+	to        State
+	listeners []func(from, to State)
+}
+
+// Must be called with the lock held
+func (b *Breaker) currentState() (State, *transition) {
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.opts.Timeout {
+		return StateHalfOpen, b.setState(StateHalfOpen)
+	}
+
+	return b.state, nil
+}
+
+// Must be called with the lock held
+func (b *Breaker) onFailure() *transition {
+	b.successes = 0
+	b.failures++
+
+	switch b.state {
+	case StateClosed:
+		if b.failures >= b.opts.FailureThreshold {
+			return b.setState(StateOpen)
+		}
+	case StateHalfOpen:
+		return b.setState(StateOpen)
+	}
+
+	return nil
+}
+
+// Must be called with the lock held
+func (b *Breaker) onSuccess() *transition {
+	b.failures = 0
+	b.successes++
+
+	if b.state == StateHalfOpen && b.successes >= b.opts.SuccessThreshold {
+		return b.setState(StateClosed)
+	}
+
+	return nil
+}
+
+// Must be called with the lock held
+func (b *Breaker) setState(state State) *transition {
+	from := b.state
+
+	b.state = state
+	b.failures = 0
+	b.successes = 0
+
+	if state == StateOpen {
+		b.openedAt = time.Now()
+	}
+
+	return &transition{
+		from:      from,
+		to:        state,
+		listeners: append([]func(from, to State){}, b.listeners...),
+	}
+}
+
+// Listeners are called without the lock held so they may use the breaker
+func (b *Breaker) notify(t *transition) {
+	if t == nil {
+		return
+	}
+
+	for _, listener := range t.listeners {
+		listener(t.from, t.to)
+	}
+}
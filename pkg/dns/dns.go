@@ -0,0 +1,291 @@
+package dns
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+const (
+	TypeA    = 1
+	TypeAAAA = 28
+
+	DefaultPort    = "53"
+	DefaultTimeout = 5 * time.Second
+
+	classIN = 1
+
+	headerSize = 12
+	// Responses without EDNS never exceed this
+	maxMessageSize = 512
+
+	flagResponse         = 1 << 15
+	flagTruncated        = 1 << 9
+	flagRecursionDesired = 1 << 8
+	rcodeMask            = 0xf
+	rcodeNameError       = 3
+
+	maxNameLength  = 255
+	maxLabelLength = 63
+	// Bounds pointer loops in malicious messages
+	maxPointerJumps = 16
+)
+
+var (
+	errUnsupportedType = errors.New("could not resolve records other than A or AAAA")
+	errInvalidName     = errors.New("could not encode invalid name")
+	errInvalidMessage  = errors.New("could not parse invalid message")
+	errTruncated       = errors.New("could not use truncated response")
+	errNotFound        = errors.New("could not find host")
+	errWrongQuestion   = errors.New("could not use response to a different question")
+)
+
+// The resolver's port defaults to 53; CNAMEs are followed as far as the resolver includes them in its answer
+func Resolve(addr, name string, qtype uint16) ([]net.IP, error) {
+	if qtype != TypeA && qtype != TypeAAAA {
+		return nil, errUnsupportedType
+	}
+
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, DefaultPort)
+	}
+
+	raddr, err := tinynet.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unpredictable IDs make spoofing responses harder
+	idBytes := make([]byte, 2)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, err
+	}
+	id := binary.BigEndian.Uint16(idBytes)
+
+	query, err := encodeQuery(id, name, qtype)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := tinynet.DialUDP("udp", nil, raddr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(DefaultTimeout)); err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, maxMessageSize)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		// Ignore stray responses to other queries
+		if n < headerSize || binary.BigEndian.Uint16(buf[0:2]) != id {
+			continue
+		}
+
+		return parseResponse(buf[:n], name, qtype)
+	}
+}
+
+func encodeQuery(id uint16, name string, qtype uint16) ([]byte, error) {
+	msg := make([]byte, headerSize, headerSize+len(name)+6)
+
+	binary.BigEndian.PutUint16(msg[0:2], id)
+	binary.BigEndian.PutUint16(msg[2:4], flagRecursionDesired)
+	binary.BigEndian.PutUint16(msg[4:6], 1) // Question count
+
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name)+2 > maxNameLength {
+		return nil, errInvalidName
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > maxLabelLength {
+			return nil, errInvalidName
+		}
+
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	msg = append(msg, 0)
+
+	question := make([]byte, 4)
+	binary.BigEndian.PutUint16(question[0:2], qtype)
+	binary.BigEndian.PutUint16(question[2:4], classIN)
+
+	return append(msg, question...), nil
+}
+
+func parseResponse(msg []byte, name string, qtype uint16) ([]net.IP, error) {
+	flags := binary.BigEndian.Uint16(msg[2:4])
+	if flags&flagResponse == 0 {
+		return nil, errInvalidMessage
+	}
+
+	// Falling back to TCP isn't supported
+	if flags&flagTruncated != 0 {
+		return nil, errTruncated
+	}
+
+	switch rcode := flags & rcodeMask; rcode {
+	case 0:
+	case rcodeNameError:
+		return nil, fmt.Errorf("%w %v", errNotFound, name)
+	default:
+		return nil, fmt.Errorf("could not resolve %v: server returned rcode %v", name, rcode)
+	}
+
+	questions := int(binary.BigEndian.Uint16(msg[4:6]))
+	answers := int(binary.BigEndian.Uint16(msg[6:8]))
+
+	// Only accept answers to the question which was asked
+	if questions != 1 {
+		return nil, errWrongQuestion
+	}
+
+	questionName, next, err := readName(msg, headerSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Type and class
+	if next+4 > len(msg) {
+		return nil, errInvalidMessage
+	}
+
+	questionType := binary.BigEndian.Uint16(msg[next : next+2])
+	questionClass := binary.BigEndian.Uint16(msg[next+2 : next+4])
+	if !strings.EqualFold(questionName, strings.TrimSuffix(name, ".")) || questionType != qtype || questionClass != classIN {
+		return nil, errWrongQuestion
+	}
+
+	offset := next + 4
+
+	ips := []net.IP{}
+	for i := 0; i < answers; i++ {
+		next, err := skipName(msg, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		// Type, class, TTL and data length
+		if next+10 > len(msg) {
+			return nil, errInvalidMessage
+		}
+
+		rtype := binary.BigEndian.Uint16(msg[next : next+2])
+		rclass := binary.BigEndian.Uint16(msg[next+2 : next+4])
+		length := int(binary.BigEndian.Uint16(msg[next+8 : next+10]))
+
+		offset = next + 10 + length
+		if offset > len(msg) {
+			return nil, errInvalidMessage
+		}
+
+		if rtype != qtype || rclass != classIN {
+			continue
+		}
+
+		data := msg[next+10 : offset]
+		if (rtype == TypeA && length != net.IPv4len) || (rtype == TypeAAAA && length != net.IPv6len) {
+			return nil, errInvalidMessage
+		}
+
+		ips = append(ips, net.IP(append([]byte{}, data...)))
+	}
+
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("%w %v", errNotFound, name)
+	}
+
+	return ips, nil
+}
+
+// Returns the name and the offset after it
+func readName(msg []byte, offset int) (string, int, error) {
+	labels := []string{}
+	next := -1
+	jumps := 0
+	for {
+		if offset >= len(msg) {
+			return "", 0, errInvalidMessage
+		}
+
+		length := int(msg[offset])
+		switch {
+		case length == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+
+			return strings.Join(labels, "."), next, nil
+		case length&0xc0 == 0xc0:
+			if offset+2 > len(msg) {
+				return "", 0, errInvalidMessage
+			}
+
+			// The name continues after the first pointer
+			if next < 0 {
+				next = offset + 2
+			}
+
+			jumps++
+			if jumps > maxPointerJumps {
+				return "", 0, errInvalidMessage
+			}
+
+			offset = int(binary.BigEndian.Uint16(msg[offset:offset+2]) & 0x3fff)
+		case length > maxLabelLength:
+			return "", 0, errInvalidMessage
+		default:
+			if offset+1+length > len(msg) {
+				return "", 0, errInvalidMessage
+			}
+
+			labels = append(labels, string(msg[offset+1:offset+1+length]))
+			offset += 1 + length
+		}
+	}
+}
+
+// Returns the offset after the name
+func skipName(msg []byte, offset int) (int, error) {
+	for {
+		if offset >= len(msg) {
+			return 0, errInvalidMessage
+		}
+
+		length := int(msg[offset])
+		switch {
+		case length == 0:
+			return offset + 1, nil
+		case length&0xc0 == 0xc0:
+			// A compression pointer always ends the name
+			if offset+2 > len(msg) {
+				return 0, errInvalidMessage
+			}
+
+			return offset + 2, nil
+		case length > maxLabelLength:
+			return 0, errInvalidMessage
+		default:
+			offset += 1 + length
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package dns
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+)
+
+// Answers the question in the query with a single A record
+func response(t *testing.T, questionName string, qtype uint16, ip net.IP) []byte {
+	t.Helper()
+
+	msg, err := encodeQuery(1, questionName, qtype)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binary.BigEndian.PutUint16(msg[2:4], flagResponse|flagRecursionDesired)
+	binary.BigEndian.PutUint16(msg[6:8], 1) // Answer count
+
+	answer := make([]byte, 12)
+	binary.BigEndian.PutUint16(answer[0:2], 0xc000|headerSize) // Points to the question's name
+	binary.BigEndian.PutUint16(answer[2:4], TypeA)
+	binary.BigEndian.PutUint16(answer[4:6], classIN)
+	binary.BigEndian.PutUint16(answer[10:12], net.IPv4len)
+
+	return append(append(msg, answer...), ip.To4()...)
+}
+
+func TestParseResponse(t *testing.T) {
+	expected := net.IPv4(192, 0, 2, 1)
+
+	ips, err := parseResponse(response(t, "example.com", TypeA, expected), "Example.com.", TypeA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ips) != 1 || !ips[0].Equal(expected) {
+		t.Fatalf("got %v, expected %v", ips, expected)
+	}
+}
+
+func TestParseResponseWrongQuestion(t *testing.T) {
+	ip := net.IPv4(192, 0, 2, 1)
+
+	if _, err := parseResponse(response(t, "attacker.example", TypeA, ip), "example.com", TypeA); !errors.Is(err, errWrongQuestion) {
+		t.Fatalf("got error %v for a different name, expected %v", err, errWrongQuestion)
+	}
+
+	if _, err := parseResponse(response(t, "example.com", TypeAAAA, ip), "example.com", TypeA); !errors.Is(err, errWrongQuestion) {
+		t.Fatalf("got error %v for a different type, expected %v", err, errWrongQuestion)
+	}
+}
@@ -0,0 +1,76 @@
+package echo
+
+import (
+	"io"
+	"net"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+func Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		go ServeConn(conn)
+	}
+}
+
+func ServeConn(conn net.Conn) {
+	defer conn.Close()
+
+	_, _ = io.Copy(conn, tinynet.NewEOFReader(conn))
+}
+
+type EchoClient struct {
+	conn net.Conn
+}
+
+func Dial(addr string) (*EchoClient, error) {
+	conn, err := tinynet.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewEchoClient(conn), nil
+}
+
+func NewEchoClient(conn net.Conn) *EchoClient {
+	return &EchoClient{conn}
+}
+
+func (c *EchoClient) Echo(b []byte) ([]byte, error) {
+	// Write concurrently so that large payloads don't fill up both socket buffers
+	writeErr := make(chan error, 1)
+	go func() {
+		for out := b; len(out) > 0; {
+			n, err := c.conn.Write(out)
+			if err != nil {
+				writeErr <- err
+
+				return
+			}
+
+			out = out[n:]
+		}
+
+		writeErr <- nil
+	}()
+
+	in := make([]byte, len(b))
+	if _, err := tinynet.ReadFull(c.conn, in); err != nil {
+		return nil, err
+	}
+
+	if err := <-writeErr; err != nil {
+		return nil, err
+	}
+
+	return in, nil
+}
+
+func (c *EchoClient) Close() error {
+	return c.conn.Close()
+}
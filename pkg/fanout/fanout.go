@@ -0,0 +1,123 @@
+package fanout
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultWriteTimeout = 5 * time.Second
+)
+
+type Broadcaster struct {
+	// Set a write deadline before each broadcast so that one slow client can't block the others
+	DeadlineAware bool
+	WriteTimeout  time.Duration
+
+	conns  map[int]net.Conn
+	nextID int
+	lock   sync.Mutex
+}
+
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{
+		WriteTimeout: DefaultWriteTimeout,
+
+		conns: map[int]net.Conn{},
+	}
+}
+
+func (b *Broadcaster) Register(conn net.Conn) int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.conns == nil {
+		b.conns = map[int]net.Conn{}
+	}
+
+	id := b.nextID
+	b.nextID++
+
+	b.conns[id] = conn
+
+	return id
+}
+
+func (b *Broadcaster) Unregister(id int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	delete(b.conns, id)
+}
+
+func (b *Broadcaster) Broadcast(msg []byte) int {
+	b.lock.Lock()
+	conns := make(map[int]net.Conn, len(b.conns))
+	for id, conn := range b.conns {
+		conns[id] = conn
+	}
+	b.lock.Unlock()
+
+	timeout := b.WriteTimeout
+	if timeout <= 0 {
+		timeout = DefaultWriteTimeout
+	}
+
+	var deadline time.Time
+	if b.DeadlineAware {
+		deadline = time.Now().Add(timeout)
+	}
+
+	failed := make(chan int, len(conns))
+
+	var wg sync.WaitGroup
+	for id, conn := range conns {
+		wg.Add(1)
+
+		go func(id int, conn net.Conn) {
+			defer wg.Done()
+
+			if err := write(conn, msg, deadline); err != nil {
+				failed <- id
+			}
+		}(id, conn)
+	}
+
+	wg.Wait()
+	close(failed)
+
+	// Drop the conns which couldn't be written to
+	errs := 0
+	b.lock.Lock()
+	for id := range failed {
+		delete(b.conns, id)
+
+		errs++
+	}
+	b.lock.Unlock()
+
+	return len(conns) - errs
+}
+
+func write(conn net.Conn, msg []byte, deadline time.Time) error {
+	if !deadline.IsZero() {
+		if err := conn.SetWriteDeadline(deadline); err != nil {
+			return err
+		}
+
+		// Don't let the deadline affect the owner's later writes
+		defer conn.SetWriteDeadline(time.Time{})
+	}
+
+	for len(msg) > 0 {
+		n, err := conn.Write(msg)
+		if err != nil {
+			return err
+		}
+
+		msg = msg[n:]
+	}
+
+	return nil
+}
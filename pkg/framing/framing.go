@@ -0,0 +1,150 @@
+package framing
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+const (
+	DefaultMaxMessageSize = 16 * 1024 * 1024
+
+	maxInt = int(^uint(0) >> 1)
+)
+
+var (
+	errInvalidHeaderSize = errors.New("could not use header size, must be 1, 2 or 4")
+	errMessageTooLarge   = errors.New("could not fit message length into header")
+	errMessageOverLimit  = errors.New("could not use message larger than MaxMessageSize")
+	errInvalidLength     = errors.New("could not use message length larger than header allows")
+
+	errMessageOverPlatformLimit = errors.New("could not allocate message larger than the platform's max int")
+)
+
+type LengthPrefixedConn struct {
+	net.Conn
+
+	// Messages above this size are rejected when reading and writing, so
+	// that a peer can't make us allocate whatever its header claims; 0
+	// disables the limit
+	MaxMessageSize uint64
+
+	headerSize int
+
+	readLock  sync.Mutex
+	writeLock sync.Mutex
+}
+
+func NewLengthPrefixedConn(inner net.Conn, headerSize int) *LengthPrefixedConn {
+	return &LengthPrefixedConn{
+		Conn: inner,
+
+		MaxMessageSize: DefaultMaxMessageSize,
+
+		headerSize: headerSize,
+	}
+}
+
+func (c *LengthPrefixedConn) maxLength() (uint64, error) {
+	switch c.headerSize {
+	case 1:
+		return 1<<8 - 1, nil
+	case 2:
+		return 1<<16 - 1, nil
+	case 4:
+		return 1<<32 - 1, nil
+	default:
+		return 0, errInvalidHeaderSize
+	}
+}
+
+func (c *LengthPrefixedConn) WriteMsg(b []byte) error {
+	max, err := c.maxLength()
+	if err != nil {
+		return err
+	}
+
+	if uint64(len(b)) > max {
+		return errMessageTooLarge
+	}
+
+	if c.MaxMessageSize > 0 && uint64(len(b)) > c.MaxMessageSize {
+		return errMessageOverLimit
+	}
+
+	// Write header and payload in one buffer so that the message is sent at once
+	msg := make([]byte, c.headerSize+len(b))
+	switch c.headerSize {
+	case 1:
+		msg[0] = byte(len(b))
+	case 2:
+		binary.BigEndian.PutUint16(msg, uint16(len(b)))
+	case 4:
+		binary.BigEndian.PutUint32(msg, uint32(len(b)))
+	}
+	copy(msg[c.headerSize:], b)
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	for len(msg) > 0 {
+		n, err := c.Conn.Write(msg)
+		if err != nil {
+			return err
+		}
+
+		msg = msg[n:]
+	}
+
+	return nil
+}
+
+func (c *LengthPrefixedConn) ReadMsg() ([]byte, error) {
+	max, err := c.maxLength()
+	if err != nil {
+		return nil, err
+	}
+
+	c.readLock.Lock()
+	defer c.readLock.Unlock()
+
+	// Read header
+	header := make([]byte, c.headerSize)
+	if _, err := tinynet.ReadFull(c.Conn, header); err != nil {
+		return nil, err
+	}
+
+	var length uint64
+	switch c.headerSize {
+	case 1:
+		length = uint64(header[0])
+	case 2:
+		length = uint64(binary.BigEndian.Uint16(header))
+	case 4:
+		length = uint64(binary.BigEndian.Uint32(header))
+	}
+
+	if length > max {
+		return nil, errInvalidLength
+	}
+
+	if c.MaxMessageSize > 0 && length > c.MaxMessageSize {
+		return nil, errMessageOverLimit
+	}
+
+	// Lengths above 2^31 - 1 don't fit into an int on 32-bit targets
+	if length > uint64(maxInt) {
+		return nil, errMessageOverPlatformLimit
+	}
+
+	// Read payload
+	msg := make([]byte, length)
+	if _, err := tinynet.ReadFull(c.Conn, msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,242 @@
+package gate
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+// A rule matches either by address (CIDR) or, for local peers, by credentials (UIDs or GIDs)
+type Rule struct {
+	CIDR  string
+	Allow bool
+
+	UIDs []uint32
+	GIDs []uint32
+}
+
+func AllowUID(uids ...uint32) Rule {
+	return Rule{
+		Allow: true,
+		UIDs:  uids,
+	}
+}
+
+func AllowGID(gids ...uint32) Rule {
+	return Rule{
+		Allow: true,
+		GIDs:  gids,
+	}
+}
+
+type rule struct {
+	network *net.IPNet
+	uids    []uint32
+	gids    []uint32
+	raw     string
+	allow   bool
+}
+
+func (r rule) matches(ip net.IP, cred *tinynet.Ucred) bool {
+	if r.network != nil {
+		return ip != nil && r.network.Contains(ip)
+	}
+
+	if cred == nil {
+		return false
+	}
+
+	for _, uid := range r.uids {
+		if uid == cred.Uid {
+			return true
+		}
+	}
+
+	for _, gid := range r.gids {
+		if gid == cred.Gid {
+			return true
+		}
+	}
+
+	return false
+}
+
+type peerCredentialer interface {
+	PeerCredentials() (*tinynet.Ucred, error)
+}
+
+type ACL struct {
+	// Used if no rule matches
+	DefaultAllow bool
+	// Records every decision, one per line
+	AuditLog io.Writer
+
+	rules   []rule
+	invalid bool
+	lock    sync.Mutex
+}
+
+// Rules that can't be parsed make the ACL deny everything so that a typo never
+// opens it up; use ParseACL to check them.
+func NewACL(rules []Rule) *ACL {
+	acl, err := ParseACL(rules)
+	if err != nil {
+		return &ACL{
+			invalid: true,
+		}
+	}
+
+	return acl
+}
+
+func ParseACL(rules []Rule) (*ACL, error) {
+	parsed := make([]rule, 0, len(rules))
+	for _, r := range rules {
+		if len(r.UIDs) > 0 || len(r.GIDs) > 0 {
+			if r.CIDR != "" {
+				return nil, fmt.Errorf("could not parse rule %v: a rule can't match both addresses and credentials", r.CIDR)
+			}
+
+			parsed = append(parsed, rule{
+				uids:  r.UIDs,
+				gids:  r.GIDs,
+				raw:   fmt.Sprintf("uids %v gids %v", r.UIDs, r.GIDs),
+				allow: r.Allow,
+			})
+
+			continue
+		}
+
+		cidr := r.CIDR
+
+		// Single addresses match only themselves
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("could not parse rule %v", r.CIDR)
+			}
+
+			if ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse rule %v: %v", r.CIDR, err)
+		}
+
+		parsed = append(parsed, rule{
+			network: network,
+			raw:     r.CIDR,
+			allow:   r.Allow,
+		})
+	}
+
+	return &ACL{
+		rules: parsed,
+	}, nil
+}
+
+// Credential rules never match here; use AllowedConn for those
+func (a *ACL) Allowed(addr net.Addr) bool {
+	allow, reason := a.decide(addrIP(addr), nil)
+
+	a.audit(remoteString(addr), allow, reason)
+
+	return allow
+}
+
+// Matches credential rules against the peer's credentials if the connection
+// provides them, e.g. *tinynet.UnixConn
+func (a *ACL) AllowedConn(conn net.Conn) bool {
+	var cred *tinynet.Ucred
+	if c, ok := conn.(peerCredentialer); ok {
+		if pc, err := c.PeerCredentials(); err == nil {
+			cred = pc
+		}
+	}
+
+	allow, reason := a.decide(addrIP(conn.RemoteAddr()), cred)
+
+	remote := remoteString(conn.RemoteAddr())
+	if cred != nil {
+		remote = fmt.Sprintf("%v (pid %v uid %v gid %v)", remote, cred.Pid, cred.Uid, cred.Gid)
+	}
+
+	a.audit(remote, allow, reason)
+
+	return allow
+}
+
+func (a *ACL) decide(ip net.IP, cred *tinynet.Ucred) (bool, string) {
+	if a.invalid {
+		return false, "invalid rules"
+	}
+
+	for _, r := range a.rules {
+		if r.matches(ip, cred) {
+			return r.allow, r.raw
+		}
+	}
+
+	return a.DefaultAllow, "default"
+}
+
+func (a *ACL) audit(remote string, allow bool, reason string) {
+	if a.AuditLog == nil {
+		return
+	}
+
+	decision := "deny"
+	if allow {
+		decision = "allow"
+	}
+
+	// Serialize writes so that lines from concurrent decisions don't interleave
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	_, _ = fmt.Fprintf(a.AuditLog, "%v %v %v (%v)\n", time.Now().Format(time.RFC3339), decision, remote, reason)
+}
+
+func remoteString(addr net.Addr) string {
+	if addr == nil {
+		return "unknown"
+	}
+
+	return addr.String()
+}
+
+func addrIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case nil:
+		return nil
+	case *tinynet.TCPAddr:
+		if a == nil {
+			return nil
+		}
+
+		return net.IP(a.IP)
+	case *net.TCPAddr:
+		if a == nil {
+			return nil
+		}
+
+		return a.IP
+	default:
+		host, _, err := net.SplitHostPort(addr.String())
+		if err != nil {
+			return nil
+		}
+
+		return net.ParseIP(host)
+	}
+}
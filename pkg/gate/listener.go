@@ -0,0 +1,33 @@
+package gate
+
+import (
+	"net"
+)
+
+type listener struct {
+	net.Listener
+
+	acl *ACL
+}
+
+func NewListener(inner net.Listener, acl *ACL) net.Listener {
+	return &listener{
+		Listener: inner,
+		acl:      acl,
+	}
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		if l.acl.AllowedConn(conn) {
+			return conn, nil
+		}
+
+		_ = conn.Close()
+	}
+}
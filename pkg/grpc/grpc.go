@@ -0,0 +1,38 @@
+package grpc
+
+import (
+	"context"
+	"net"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+	"google.golang.org/grpc"
+)
+
+type Dialer struct {
+	Config *tinynet.DialConfig
+}
+
+func NewDialer() *Dialer {
+	return &Dialer{
+		Config: tinynet.NewDialConfig(),
+	}
+}
+
+// DialContext matches the function expected by grpc.WithContextDialer. Addresses
+// are resolved by tinynet's resolver, so use the passthrough scheme in the target.
+func (d *Dialer) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	config := d.Config
+	if config == nil {
+		config = tinynet.NewDialConfig()
+	}
+
+	return config.Dial(ctx, "tcp", address)
+}
+
+func (d *Dialer) DialOption() grpc.DialOption {
+	return grpc.WithContextDialer(d.DialContext)
+}
+
+func DialOption() grpc.DialOption {
+	return NewDialer().DialOption()
+}
@@ -0,0 +1,182 @@
+package httputil
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+type ReverseProxy struct {
+	Backend string
+	Dial    func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+func NewReverseProxy(backend string) *ReverseProxy {
+	return &ReverseProxy{
+		Backend: backend,
+		Dial:    tinynet.DialContext,
+	}
+}
+
+func (p *ReverseProxy) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		go p.ServeConn(conn)
+	}
+}
+
+func (p *ReverseProxy) ServeConn(conn net.Conn) {
+	defer conn.Close()
+
+	client := bufio.NewReader(tinynet.NewEOFReader(conn))
+
+	var backendConn net.Conn
+	var backend *bufio.Reader
+	defer func() {
+		if backendConn != nil {
+			_ = backendConn.Close()
+		}
+	}()
+
+	for {
+		req, err := http.ReadRequest(client)
+		if err != nil {
+			return
+		}
+
+		// Connect to the backend lazily and re-use it for the client's connection
+		if backendConn == nil {
+			dial := p.Dial
+			if dial == nil {
+				dial = tinynet.DialContext
+			}
+
+			backendConn, err = dial(context.Background(), "tcp", p.Backend)
+			if err != nil {
+				backendConn = nil
+
+				_, _ = io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\nContent-Length: 0\r\nConnection: close\r\n\r\n")
+
+				return
+			}
+
+			backend = bufio.NewReader(tinynet.NewEOFReader(backendConn))
+		}
+
+		upgrade := isUpgrade(req.Header)
+		upgradeType := req.Header.Get("Upgrade")
+
+		removeHopHeaders(req.Header)
+		if upgrade {
+			req.Header.Set("Connection", "Upgrade")
+			req.Header.Set("Upgrade", upgradeType)
+		}
+
+		if host, _, err := net.SplitHostPort(conn.RemoteAddr().String()); err == nil {
+			if prior := req.Header.Get("X-Forwarded-For"); prior != "" {
+				host = prior + ", " + host
+			}
+
+			req.Header.Set("X-Forwarded-For", host)
+		}
+
+		// Forward request
+		if err := req.Write(backendConn); err != nil {
+			return
+		}
+
+		res, err := http.ReadResponse(backend, req)
+		if err != nil {
+			_, _ = io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\nContent-Length: 0\r\nConnection: close\r\n\r\n")
+
+			return
+		}
+
+		// Switch to a transparent tunnel, e.g. for WebSockets
+		if upgrade && res.StatusCode == http.StatusSwitchingProtocols {
+			if err := res.Write(conn); err != nil {
+				return
+			}
+
+			tunnel(conn, client, backendConn, backend)
+
+			return
+		}
+
+		removeHopHeaders(res.Header)
+
+		// Stream response
+		err = res.Write(conn)
+		_ = res.Body.Close()
+		if err != nil || req.Close || res.Close {
+			return
+		}
+	}
+}
+
+func isUpgrade(header http.Header) bool {
+	for _, value := range header["Connection"] {
+		for _, token := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func removeHopHeaders(header http.Header) {
+	// Headers listed in Connection are hop-by-hop too
+	for _, value := range header["Connection"] {
+		for _, token := range strings.Split(value, ",") {
+			if token = strings.TrimSpace(token); token != "" {
+				header.Del(token)
+			}
+		}
+	}
+
+	for _, key := range hopHeaders {
+		header.Del(key)
+	}
+}
+
+func tunnel(client net.Conn, clientReader io.Reader, backend net.Conn, backendReader io.Reader) {
+	done := make(chan struct{}, 2)
+
+	go func() {
+		_, _ = io.Copy(backend, clientReader)
+
+		done <- struct{}{}
+	}()
+
+	go func() {
+		_, _ = io.Copy(client, backendReader)
+
+		done <- struct{}{}
+	}()
+
+	// Tear down both directions once either side is done
+	<-done
+}
@@ -0,0 +1,164 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+var (
+	errClientClosed = errors.New("could not call on closed client")
+)
+
+type Client struct {
+	conn   net.Conn
+	writer *messageWriter
+
+	nextID  uint64
+	pending map[uint64]chan response
+	err     error
+	lock    sync.Mutex
+}
+
+func NewClient(addr string) (*Client, error) {
+	conn, err := tinynet.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClientConn(conn), nil
+}
+
+func NewClientConn(conn net.Conn) *Client {
+	c := &Client{
+		conn:    conn,
+		writer:  &messageWriter{conn: conn},
+		pending: map[uint64]chan response{},
+	}
+
+	go c.recvLoop()
+
+	return c
+}
+
+func (c *Client) Call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	if c.err != nil {
+		c.lock.Unlock()
+
+		return c.err
+	}
+
+	c.nextID++
+	id := c.nextID
+
+	done := make(chan response, 1)
+	c.pending[id] = done
+	c.lock.Unlock()
+
+	defer func() {
+		c.lock.Lock()
+		delete(c.pending, id)
+		c.lock.Unlock()
+	}()
+
+	if err := c.writer.writeMessage(request{
+		Version: version,
+		Method:  method,
+		Params:  rawParams,
+		ID:      json.RawMessage(strconv.FormatUint(id, 10)),
+	}); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res, ok := <-done:
+		if !ok {
+			c.lock.Lock()
+			defer c.lock.Unlock()
+
+			return c.err
+		}
+
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if result == nil {
+			return nil
+		}
+
+		return json.Unmarshal(res.Result, result)
+	}
+}
+
+func (c *Client) Notify(method string, params interface{}) error {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	return c.writer.writeMessage(request{
+		Version: version,
+		Method:  method,
+		Params:  rawParams,
+	})
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) recvLoop() {
+	reader := bufio.NewReader(tinynet.NewEOFReader(c.conn))
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			break
+		}
+
+		res := response{}
+		if err := json.Unmarshal(line, &res); err != nil {
+			// Skip malformed responses; the matching call will time out through its context
+			continue
+		}
+
+		id, err := strconv.ParseUint(string(res.ID), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		c.lock.Lock()
+		done, ok := c.pending[id]
+		delete(c.pending, id)
+		c.lock.Unlock()
+
+		if ok {
+			done <- res
+		}
+	}
+
+	// Fail all calls that are still waiting for a response
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.err = errClientClosed
+	for id, done := range c.pending {
+		close(done)
+		delete(c.pending, id)
+	}
+}
@@ -0,0 +1,72 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+)
+
+const (
+	version = "2.0"
+
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	CodeServerError    = -32000
+)
+
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonrpc error %v: %v", e.Code, e.Message)
+}
+
+type request struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+type response struct {
+	Version string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type messageWriter struct {
+	conn net.Conn
+	lock sync.Mutex
+}
+
+func (w *messageWriter) writeMessage(v interface{}) error {
+	msg, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	// Messages are delimited by newlines, which json.Marshal never emits
+	msg = append(msg, '\n')
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for len(msg) > 0 {
+		n, err := w.conn.Write(msg)
+		if err != nil {
+			return err
+		}
+
+		msg = msg[n:]
+	}
+
+	return nil
+}
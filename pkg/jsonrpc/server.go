@@ -0,0 +1,120 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"sync"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+type Handler func(params json.RawMessage) (interface{}, error)
+
+type Server struct {
+	addr     string
+	handlers map[string]Handler
+	lock     sync.RWMutex
+}
+
+func NewServer(addr string) *Server {
+	return &Server{
+		addr:     addr,
+		handlers: map[string]Handler{},
+	}
+}
+
+func (s *Server) Register(name string, handler Handler) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.handlers[name] = handler
+}
+
+func (s *Server) ListenAndServe() error {
+	return tinynet.ListenAndServe(s.addr, s.ServeConn)
+}
+
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		go s.ServeConn(conn)
+	}
+}
+
+func (s *Server) ServeConn(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(tinynet.NewEOFReader(conn))
+	writer := &messageWriter{conn: conn}
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		req := request{}
+		if err := json.Unmarshal(line, &req); err != nil {
+			if err := writer.writeMessage(response{
+				Version: version,
+				Error:   &Error{Code: CodeParseError, Message: err.Error()},
+				ID:      json.RawMessage("null"),
+			}); err != nil {
+				return
+			}
+
+			continue
+		}
+
+		// Handle requests concurrently so that a slow method doesn't block the connection
+		go s.handle(writer, req)
+	}
+}
+
+func (s *Server) handle(writer *messageWriter, req request) {
+	res := response{
+		Version: version,
+		ID:      req.ID,
+	}
+
+	if req.Version != version || req.Method == "" {
+		res.Error = &Error{Code: CodeInvalidRequest, Message: "invalid request"}
+	} else {
+		s.lock.RLock()
+		handler, ok := s.handlers[req.Method]
+		s.lock.RUnlock()
+
+		if !ok {
+			res.Error = &Error{Code: CodeMethodNotFound, Message: "method not found"}
+		} else {
+			result, err := handler(req.Params)
+			if err != nil {
+				if rpcErr, ok := err.(*Error); ok {
+					res.Error = rpcErr
+				} else {
+					res.Error = &Error{Code: CodeServerError, Message: err.Error()}
+				}
+			} else if res.Result, err = json.Marshal(result); err != nil {
+				res.Error = &Error{Code: CodeInternalError, Message: err.Error()}
+			}
+		}
+	}
+
+	// Notifications don't get a response, not even on errors
+	if len(req.ID) == 0 {
+		return
+	}
+
+	_ = writer.writeMessage(res)
+}
@@ -0,0 +1,217 @@
+package loadbalance
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+const (
+	DefaultMaxRetries = 2
+	DefaultCooldown   = 10 * time.Second
+)
+
+var (
+	errNoBackends = errors.New("could not dial without backends")
+)
+
+type backend struct {
+	// Accessed atomically; kept first for 64-bit alignment on 32-bit platforms
+	active int64
+
+	addr      string
+	downUntil time.Time
+}
+
+type pool struct {
+	// Number of other backends to try after a failed dial
+	MaxRetries int
+	// Time a failing backend is taken out of rotation
+	Cooldown time.Duration
+	Dialer   tinynet.ContextDialer
+
+	backends []*backend
+	lock     sync.Mutex
+}
+
+func newPool(addrs []string) pool {
+	backends := make([]*backend, 0, len(addrs))
+	for _, addr := range addrs {
+		backends = append(backends, &backend{addr: addr})
+	}
+
+	return pool{
+		MaxRetries: DefaultMaxRetries,
+		Cooldown:   DefaultCooldown,
+		Dialer:     tinynet.NewDialConfig(),
+
+		backends: backends,
+	}
+}
+
+// Backends in their cool-down period are only used if all others have been tried
+func (p *pool) candidates(tried map[*backend]bool) []*backend {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := time.Now()
+
+	healthy := []*backend{}
+	down := []*backend{}
+	for _, b := range p.backends {
+		if tried[b] {
+			continue
+		}
+
+		if now.Before(b.downUntil) {
+			down = append(down, b)
+		} else {
+			healthy = append(healthy, b)
+		}
+	}
+
+	if len(healthy) > 0 {
+		return healthy
+	}
+
+	return down
+}
+
+func (p *pool) markDown(b *backend) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	b.downUntil = time.Now().Add(p.Cooldown)
+}
+
+func (p *pool) dial(ctx context.Context, network string, pick func([]*backend) *backend) (net.Conn, *backend, error) {
+	dialer := p.Dialer
+	if dialer == nil {
+		dialer = tinynet.NewDialConfig()
+	}
+
+	tried := map[*backend]bool{}
+
+	lastErr := errNoBackends
+	for attempt := 0; attempt <= p.MaxRetries; attempt++ {
+		candidates := p.candidates(tried)
+		if len(candidates) == 0 {
+			break
+		}
+
+		b := pick(candidates)
+		tried[b] = true
+
+		conn, err := dialer.Dial(ctx, network, b.addr)
+		if err == nil {
+			return conn, b, nil
+		}
+
+		lastErr = err
+
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+
+		p.markDown(b)
+	}
+
+	return nil, nil, lastErr
+}
+
+type RoundRobinDialer struct {
+	pool
+
+	next uint64
+}
+
+func NewRoundRobinDialer(addrs []string) *RoundRobinDialer {
+	return &RoundRobinDialer{
+		pool: newPool(addrs),
+	}
+}
+
+// The address is ignored; connections go to one of the backends
+func (d *RoundRobinDialer) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, _, err := d.dial(ctx, network, func(candidates []*backend) *backend {
+		return candidates[(atomic.AddUint64(&d.next, 1)-1)%uint64(len(candidates))]
+	})
+
+	return conn, err
+}
+
+type RandomDialer struct {
+	pool
+}
+
+func NewRandomDialer(addrs []string) *RandomDialer {
+	return &RandomDialer{
+		pool: newPool(addrs),
+	}
+}
+
+// The address is ignored; connections go to one of the backends
+func (d *RandomDialer) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, _, err := d.dial(ctx, network, func(candidates []*backend) *backend {
+		return candidates[rand.Intn(len(candidates))]
+	})
+
+	return conn, err
+}
+
+type LeastConnectionsDialer struct {
+	pool
+}
+
+func NewLeastConnectionsDialer(addrs []string) *LeastConnectionsDialer {
+	return &LeastConnectionsDialer{
+		pool: newPool(addrs),
+	}
+}
+
+// The address is ignored; connections go to one of the backends
+func (d *LeastConnectionsDialer) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, b, err := d.dial(ctx, network, func(candidates []*backend) *backend {
+		least := candidates[0]
+		for _, candidate := range candidates[1:] {
+			if atomic.LoadInt64(&candidate.active) < atomic.LoadInt64(&least.active) {
+				least = candidate
+			}
+		}
+
+		return least
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	atomic.AddInt64(&b.active, 1)
+
+	return &countedConn{
+		Conn:    conn,
+		backend: b,
+	}, nil
+}
+
+type countedConn struct {
+	net.Conn
+
+	backend   *backend
+	closeOnce sync.Once
+}
+
+func (c *countedConn) Close() error {
+	err := c.Conn.Close()
+
+	c.closeOnce.Do(func() {
+		atomic.AddInt64(&c.backend.active, -1)
+	})
+
+	return err
+}
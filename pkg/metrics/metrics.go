@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type collectors struct {
+	connections *prometheus.GaugeVec
+	received    *prometheus.CounterVec
+	sent        *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+}
+
+func newCollectors(labelNames []string) *collectors {
+	return &collectors{
+		connections: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "connection_count",
+			Help: "Number of currently open connections.",
+		}, labelNames),
+		received: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "bytes_received_total",
+			Help: "Total number of bytes received.",
+		}, labelNames),
+		sent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "bytes_sent_total",
+			Help: "Total number of bytes sent.",
+		}, labelNames),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "connection_duration_seconds",
+			Help:    "Duration of closed connections in seconds.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 4, 12),
+		}, labelNames),
+	}
+}
+
+func (c *collectors) register(reg prometheus.Registerer) {
+	register := func(collector prometheus.Collector) prometheus.Collector {
+		if err := reg.Register(collector); err != nil {
+			// Share collectors which have already been registered, e.g. by another listener
+			if existing, ok := err.(prometheus.AlreadyRegisteredError); ok {
+				return existing.ExistingCollector
+			}
+
+			tinynet.DefaultLogger().Error("could not register metrics", "err", err)
+		}
+
+		return collector
+	}
+
+	if existing, ok := register(c.connections).(*prometheus.GaugeVec); ok {
+		c.connections = existing
+	}
+
+	if existing, ok := register(c.received).(*prometheus.CounterVec); ok {
+		c.received = existing
+	}
+
+	if existing, ok := register(c.sent).(*prometheus.CounterVec); ok {
+		c.sent = existing
+	}
+
+	if existing, ok := register(c.duration).(*prometheus.HistogramVec); ok {
+		c.duration = existing
+	}
+}
+
+var (
+	// Connections wrapped directly are reported to the default registry, grouped by their label names
+	defaultCollectors     = map[string]*collectors{}
+	defaultCollectorsLock sync.Mutex
+)
+
+func collectorsForLabels(labels prometheus.Labels) *collectors {
+	names := []string{}
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	key := strings.Join(names, ",")
+
+	defaultCollectorsLock.Lock()
+	defer defaultCollectorsLock.Unlock()
+
+	c, ok := defaultCollectors[key]
+	if !ok {
+		c = newCollectors(names)
+		c.register(prometheus.DefaultRegisterer)
+
+		defaultCollectors[key] = c
+	}
+
+	return c
+}
+
+type metricsListener struct {
+	net.Listener
+
+	collectors *collectors
+}
+
+func NewMetricsListener(inner net.Listener, reg prometheus.Registerer) net.Listener {
+	c := newCollectors(nil)
+	c.register(reg)
+
+	return &metricsListener{
+		Listener:   inner,
+		collectors: c,
+	}
+}
+
+func (l *metricsListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return newMetricsConn(conn, l.collectors, prometheus.Labels{}), nil
+}
+
+type metricsConn struct {
+	net.Conn
+
+	received prometheus.Counter
+	sent     prometheus.Counter
+	duration prometheus.Observer
+	open     prometheus.Gauge
+
+	opened    time.Time
+	closeOnce sync.Once
+}
+
+func NewMetricsConn(inner net.Conn, labels prometheus.Labels) net.Conn {
+	if labels == nil {
+		labels = prometheus.Labels{}
+	}
+
+	return newMetricsConn(inner, collectorsForLabels(labels), labels)
+}
+
+func newMetricsConn(inner net.Conn, c *collectors, labels prometheus.Labels) *metricsConn {
+	conn := &metricsConn{
+		Conn: inner,
+
+		received: c.received.With(labels),
+		sent:     c.sent.With(labels),
+		duration: c.duration.With(labels),
+		open:     c.connections.With(labels),
+
+		opened: time.Now(),
+	}
+
+	conn.open.Inc()
+
+	return conn
+}
+
+func (c *metricsConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.received.Add(float64(n))
+	}
+
+	return n, err
+}
+
+func (c *metricsConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.sent.Add(float64(n))
+	}
+
+	return n, err
+}
+
+func (c *metricsConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.open.Dec()
+		c.duration.Observe(time.Since(c.opened).Seconds())
+	})
+
+	return c.Conn.Close()
+}
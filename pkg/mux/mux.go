@@ -0,0 +1,496 @@
+package mux
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+const (
+	headerSize     = 8
+	maxPayloadSize = 1<<16 - 1
+
+	acceptBacklog = 128
+	// Streams whose unread data exceeds this are reset so that the shared connection never stalls
+	maxStreamBuffer = 1 << 20
+)
+
+const (
+	flagSYN uint16 = 1 << iota
+	flagFIN
+	flagRST
+)
+
+var (
+	errMuxClosed    = errors.New("could not use closed multiplexer")
+	errStreamClosed = errors.New("use of closed stream")
+	errStreamReset  = errors.New("could not use reset stream")
+)
+
+// Frame header: stream ID (4 bytes), payload length (2 bytes), flags (2 bytes)
+type header [headerSize]byte
+
+func (h *header) encode(id uint32, length int, flags uint16) {
+	binary.BigEndian.PutUint32(h[0:4], id)
+	binary.BigEndian.PutUint16(h[4:6], uint16(length))
+	binary.BigEndian.PutUint16(h[6:8], flags)
+}
+
+func (h *header) id() uint32 {
+	return binary.BigEndian.Uint32(h[0:4])
+}
+
+func (h *header) length() int {
+	return int(binary.BigEndian.Uint16(h[4:6]))
+}
+
+func (h *header) flags() uint16 {
+	return binary.BigEndian.Uint16(h[6:8])
+}
+
+type Mux struct {
+	conn net.Conn
+
+	nextID  uint32
+	streams map[uint32]*stream
+	accept  chan *stream
+	lock    sync.Mutex
+
+	writeLock sync.Mutex
+
+	closed    chan struct{}
+	closeOnce sync.Once
+	err       error
+}
+
+func NewClient(conn net.Conn) *Mux {
+	return newMux(conn, 1)
+}
+
+func NewServer(conn net.Conn) *Mux {
+	return newMux(conn, 2)
+}
+
+func newMux(conn net.Conn, firstID uint32) *Mux {
+	m := &Mux{
+		conn: conn,
+
+		// Clients use odd, servers use even stream IDs so that they never collide
+		nextID:  firstID,
+		streams: map[uint32]*stream{},
+		accept:  make(chan *stream, acceptBacklog),
+
+		closed: make(chan struct{}),
+	}
+
+	go m.recvLoop()
+
+	return m
+}
+
+func (m *Mux) Open() (net.Conn, error) {
+	m.lock.Lock()
+	if m.isClosed() {
+		m.lock.Unlock()
+
+		return nil, errMuxClosed
+	}
+
+	id := m.nextID
+	m.nextID += 2
+
+	s := newStream(id, m)
+	m.streams[id] = s
+	m.lock.Unlock()
+
+	if err := m.writeFrame(id, nil, flagSYN); err != nil {
+		m.removeStream(id)
+
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (m *Mux) Accept() (net.Conn, error) {
+	select {
+	case s := <-m.accept:
+		return s, nil
+	case <-m.closed:
+		return nil, m.closeErr()
+	}
+}
+
+func (m *Mux) Addr() net.Addr {
+	return m.conn.LocalAddr()
+}
+
+func (m *Mux) Close() error {
+	m.shutdown(errMuxClosed)
+
+	return m.conn.Close()
+}
+
+func (m *Mux) isClosed() bool {
+	select {
+	case <-m.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *Mux) closeErr() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.err
+}
+
+func (m *Mux) shutdown(err error) {
+	m.closeOnce.Do(func() {
+		m.lock.Lock()
+		m.err = err
+		m.lock.Unlock()
+
+		close(m.closed)
+	})
+}
+
+func (m *Mux) removeStream(id uint32) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.streams, id)
+}
+
+func (m *Mux) resetStream(s *stream) {
+	m.removeStream(s.id)
+	s.reset()
+
+	// Writing from the receive loop could deadlock if the peer is blocked on writing too
+	go func() {
+		_ = m.writeFrame(s.id, nil, flagRST)
+	}()
+}
+
+func (m *Mux) writeFrame(id uint32, payload []byte, flags uint16) error {
+	if m.isClosed() {
+		return m.closeErr()
+	}
+
+	// Send header and payload at once so that frames of different streams don't interleave
+	frame := make([]byte, headerSize+len(payload))
+	h := header{}
+	h.encode(id, len(payload), flags)
+	copy(frame, h[:])
+	copy(frame[headerSize:], payload)
+
+	m.writeLock.Lock()
+	defer m.writeLock.Unlock()
+
+	for len(frame) > 0 {
+		n, err := m.conn.Write(frame)
+		if err != nil {
+			m.shutdown(err)
+
+			return err
+		}
+
+		frame = frame[n:]
+	}
+
+	return nil
+}
+
+func (m *Mux) recvLoop() {
+	h := header{}
+	for {
+		if _, err := tinynet.ReadFull(m.conn, h[:]); err != nil {
+			m.shutdown(err)
+
+			return
+		}
+
+		payload := make([]byte, h.length())
+		if _, err := tinynet.ReadFull(m.conn, payload); err != nil {
+			m.shutdown(err)
+
+			return
+		}
+
+		id, flags := h.id(), h.flags()
+
+		m.lock.Lock()
+		s, ok := m.streams[id]
+		if !ok && flags&flagSYN != 0 {
+			s = newStream(id, m)
+			m.streams[id] = s
+		}
+		m.lock.Unlock()
+
+		// Frames for unknown or already closed streams are dropped
+		if s == nil {
+			continue
+		}
+
+		if flags&flagRST != 0 {
+			m.removeStream(id)
+			s.reset()
+
+			continue
+		}
+
+		// Refuse streams instead of blocking all others while the backlog is full
+		if flags&flagSYN != 0 && !ok {
+			select {
+			case m.accept <- s:
+			default:
+				m.resetStream(s)
+
+				continue
+			}
+		}
+
+		if len(payload) > 0 && !s.push(payload) {
+			m.resetStream(s)
+
+			continue
+		}
+
+		if flags&flagFIN != 0 {
+			s.remoteClose()
+		}
+	}
+}
+
+type stream struct {
+	id  uint32
+	mux *Mux
+
+	buf           bytes.Buffer
+	closed        bool
+	remoteClosed  bool
+	isReset       bool
+	readDeadline  time.Time
+	writeDeadline time.Time
+	lock          sync.Mutex
+
+	notify chan struct{}
+}
+
+func newStream(id uint32, m *Mux) *stream {
+	return &stream{
+		id:     id,
+		mux:    m,
+		notify: make(chan struct{}, 1),
+	}
+}
+
+func (s *stream) wake() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Returns false if the buffer would overflow
+func (s *stream) push(b []byte) bool {
+	s.lock.Lock()
+	if s.buf.Len()+len(b) > maxStreamBuffer {
+		s.lock.Unlock()
+
+		return false
+	}
+	s.buf.Write(b)
+	s.lock.Unlock()
+
+	s.wake()
+
+	return true
+}
+
+func (s *stream) reset() {
+	s.lock.Lock()
+	s.isReset = true
+	s.buf.Reset()
+	s.lock.Unlock()
+
+	s.wake()
+}
+
+func (s *stream) remoteClose() {
+	s.lock.Lock()
+	s.remoteClosed = true
+	s.lock.Unlock()
+
+	s.wake()
+}
+
+func (s *stream) Read(b []byte) (int, error) {
+	for {
+		s.lock.Lock()
+		if s.buf.Len() > 0 {
+			n, _ := s.buf.Read(b)
+			s.lock.Unlock()
+
+			return n, nil
+		}
+
+		if s.closed {
+			s.lock.Unlock()
+
+			return 0, errStreamClosed
+		}
+
+		if s.isReset {
+			s.lock.Unlock()
+
+			return 0, errStreamReset
+		}
+
+		if s.remoteClosed {
+			s.lock.Unlock()
+
+			return 0, io.EOF
+		}
+
+		deadline := s.readDeadline
+		s.lock.Unlock()
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if !deadline.IsZero() {
+			d := time.Until(deadline)
+			if d <= 0 {
+				return 0, os.ErrDeadlineExceeded
+			}
+
+			timer = time.NewTimer(d)
+			timeout = timer.C
+		}
+
+		muxClosed := false
+		select {
+		case <-s.notify:
+		case <-timeout:
+		case <-s.mux.closed:
+			muxClosed = true
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+
+		// Deliver data which arrived before the multiplexer was closed
+		if muxClosed {
+			s.lock.Lock()
+			empty := s.buf.Len() == 0
+			s.lock.Unlock()
+
+			if empty {
+				return 0, s.mux.closeErr()
+			}
+		}
+	}
+}
+
+func (s *stream) Write(b []byte) (int, error) {
+	s.lock.Lock()
+	closed, isReset := s.closed, s.isReset
+	deadline := s.writeDeadline
+	s.lock.Unlock()
+
+	if closed {
+		return 0, errStreamClosed
+	}
+
+	if isReset {
+		return 0, errStreamReset
+	}
+
+	if !deadline.IsZero() && !time.Now().Before(deadline) {
+		return 0, os.ErrDeadlineExceeded
+	}
+
+	written := 0
+	for len(b) > 0 {
+		chunk := b
+		if len(chunk) > maxPayloadSize {
+			chunk = chunk[:maxPayloadSize]
+		}
+
+		if err := s.mux.writeFrame(s.id, chunk, 0); err != nil {
+			return written, err
+		}
+
+		written += len(chunk)
+		b = b[len(chunk):]
+	}
+
+	return written, nil
+}
+
+func (s *stream) Close() error {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+
+		return nil
+	}
+	s.closed = true
+	isReset := s.isReset
+	s.lock.Unlock()
+
+	s.wake()
+	s.mux.removeStream(s.id)
+
+	if isReset || s.mux.isClosed() {
+		return nil
+	}
+
+	return s.mux.writeFrame(s.id, nil, flagFIN)
+}
+
+func (s *stream) LocalAddr() net.Addr {
+	return s.mux.conn.LocalAddr()
+}
+
+func (s *stream) RemoteAddr() net.Addr {
+	return s.mux.conn.RemoteAddr()
+}
+
+func (s *stream) SetDeadline(t time.Time) error {
+	if err := s.SetReadDeadline(t); err != nil {
+		return err
+	}
+
+	return s.SetWriteDeadline(t)
+}
+
+func (s *stream) SetReadDeadline(t time.Time) error {
+	s.lock.Lock()
+	s.readDeadline = t
+	s.lock.Unlock()
+
+	// Re-evaluate the deadline in blocked reads
+	s.wake()
+
+	return nil
+}
+
+func (s *stream) SetWriteDeadline(t time.Time) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.writeDeadline = t
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package mux
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func newPair(t *testing.T) (*Mux, *Mux) {
+	clientConn, serverConn := net.Pipe()
+
+	client, server := NewClient(clientConn), NewServer(serverConn)
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = server.Close()
+	})
+
+	return client, server
+}
+
+func waitForReset(t *testing.T, s net.Conn) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := s.Write([]byte{0}); errors.Is(err, errStreamReset) {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("stream was not reset")
+}
+
+func TestStreamOverflow(t *testing.T) {
+	client, server := newPair(t)
+
+	flooded, err := client.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := flooded.Write(make([]byte, maxStreamBuffer+1)); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForReset(t, flooded)
+
+	if _, err := server.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Other streams must keep working
+	other, err := client.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accepted, err := server.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte("hello")
+	if _, err := other.Write(expected); err != nil {
+		t.Fatal(err)
+	}
+
+	actual := make([]byte, len(expected))
+	if _, err := io.ReadFull(accepted, actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, expected) {
+		t.Fatalf("read %q, expected %q", actual, expected)
+	}
+}
+
+func TestFullAcceptBacklog(t *testing.T) {
+	client, server := newPair(t)
+
+	streams := []net.Conn{}
+	for i := 0; i < acceptBacklog; i++ {
+		s, err := client.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		streams = append(streams, s)
+	}
+
+	refused, err := client.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitForReset(t, refused)
+
+	// Streams in the backlog must still receive data
+	if _, err := streams[0].Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	accepted, err := server.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := accepted.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	actual := make([]byte, 5)
+	if _, err := io.ReadFull(accepted, actual); err != nil {
+		t.Fatal(err)
+	}
+}
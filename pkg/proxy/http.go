@@ -0,0 +1,131 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type HTTPConnectDialer struct {
+	ProxyAddr string
+	Username  string
+	Password  string
+	Header    http.Header
+}
+
+func (d *HTTPConnectDialer) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	if _, _, err := splitHostPort(address); err != nil {
+		return nil, err
+	}
+
+	if err := d.checkRequest(address); err != nil {
+		return nil, err
+	}
+
+	conn, err := dialProxy(ctx, network, d.ProxyAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	tunnel, err := d.handshake(conn, address)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	if err := finishHandshake(conn); err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return tunnel, nil
+}
+
+// Line breaks would let callers inject headers or whole requests
+func validHeaderValue(value string) bool {
+	return !strings.ContainsAny(value, "\r\n")
+}
+
+func validHeaderKey(key string) bool {
+	return key != "" && !strings.ContainsAny(key, "\r\n: ")
+}
+
+func (d *HTTPConnectDialer) checkRequest(address string) error {
+	if !validHeaderValue(address) || strings.Contains(address, " ") {
+		return fmt.Errorf("could not use invalid address %q", address)
+	}
+
+	for key, values := range d.Header {
+		if !validHeaderKey(key) {
+			return fmt.Errorf("could not use invalid header key %q", key)
+		}
+
+		for _, value := range values {
+			if !validHeaderValue(value) {
+				return fmt.Errorf("could not use invalid value for header %v", key)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *HTTPConnectDialer) handshake(conn net.Conn, address string) (net.Conn, error) {
+	req := "CONNECT " + address + " HTTP/1.1\r\n"
+	req += "Host: " + address + "\r\n"
+
+	if d.Username != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte(d.Username + ":" + d.Password))
+
+		req += "Proxy-Authorization: Basic " + credentials + "\r\n"
+	}
+
+	for key, values := range d.Header {
+		for _, value := range values {
+			req += key + ": " + value + "\r\n"
+		}
+	}
+
+	req += "\r\n"
+
+	if _, err := conn.Write([]byte(req)); err != nil {
+		return nil, err
+	}
+
+	// Read response
+	reader := bufio.NewReader(conn)
+	res, err := http.ReadResponse(reader, &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not connect via HTTP proxy: %v", res.Status)
+	}
+
+	return &tunnelConn{
+		Conn:   conn,
+		reader: reader,
+	}, nil
+}
+
+type tunnelConn struct {
+	net.Conn
+
+	reader *bufio.Reader
+}
+
+func (c *tunnelConn) Read(b []byte) (int, error) {
+	// Drain bytes which were buffered while reading the response first
+	if c.reader.Buffered() > 0 {
+		return c.reader.Read(b)
+	}
+
+	return c.Conn.Read(b)
+}
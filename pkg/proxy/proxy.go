@@ -0,0 +1,55 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+type Dialer interface {
+	Dial(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+func dialProxy(ctx context.Context, network, proxyAddr string) (net.Conn, error) {
+	if network != "tcp" {
+		return nil, fmt.Errorf("unsupported network %v", network)
+	}
+
+	conn, err := tinynet.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bound the handshake by the context's deadline
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			_ = conn.Close()
+
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+func finishHandshake(conn net.Conn) error {
+	return conn.SetDeadline(time.Time{})
+}
+
+func splitHostPort(address string) (string, int, error) {
+	host, rawPort, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", 0, err
+	}
+
+	port, err := strconv.Atoi(rawPort)
+	if err != nil || port < 0 || port > 65535 {
+		return "", 0, fmt.Errorf("could not parse port %v", rawPort)
+	}
+
+	return host, port, nil
+}
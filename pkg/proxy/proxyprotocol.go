@@ -0,0 +1,279 @@
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+const (
+	DefaultPROXYHeaderTimeout = 5 * time.Second
+
+	maxPROXYv1HeaderLength = 107
+	proxyv2HeaderLength    = 16
+)
+
+var (
+	proxyv1Prefix    = []byte("PROXY ")
+	proxyv2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+	errMissingPROXYHeader = errors.New("could not find PROXY protocol header")
+	errInvalidPROXYHeader = errors.New("could not parse PROXY protocol header")
+)
+
+type PROXYListener struct {
+	net.Listener
+
+	// Strict rejects connections without a PROXY protocol header instead of passing them through
+	Strict        bool
+	HeaderTimeout time.Duration
+}
+
+func NewPROXYListener(inner net.Listener) net.Listener {
+	return &PROXYListener{
+		Listener:      inner,
+		HeaderTimeout: DefaultPROXYHeaderTimeout,
+	}
+}
+
+func (l *PROXYListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	// The header is parsed lazily so that a slow client can't block Accept
+	return &proxyProtocolConn{
+		Conn:     conn,
+		listener: l,
+		reader:   bufio.NewReader(tinynet.NewEOFReader(conn)),
+	}, nil
+}
+
+type proxyProtocolConn struct {
+	net.Conn
+
+	listener *PROXYListener
+	reader   *bufio.Reader
+
+	once  sync.Once
+	err   error
+	raddr net.Addr
+	laddr net.Addr
+}
+
+func (c *proxyProtocolConn) Read(b []byte) (int, error) {
+	c.once.Do(c.readHeader)
+
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	return c.reader.Read(b)
+}
+
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+
+	if c.raddr != nil {
+		return c.raddr
+	}
+
+	return c.Conn.RemoteAddr()
+}
+
+func (c *proxyProtocolConn) LocalAddr() net.Addr {
+	c.once.Do(c.readHeader)
+
+	if c.laddr != nil {
+		return c.laddr
+	}
+
+	return c.Conn.LocalAddr()
+}
+
+func (c *proxyProtocolConn) readHeader() {
+	if c.listener.HeaderTimeout > 0 {
+		if err := c.Conn.SetReadDeadline(time.Now().Add(c.listener.HeaderTimeout)); err != nil {
+			c.err = err
+
+			return
+		}
+
+		defer func() {
+			if err := c.Conn.SetReadDeadline(time.Time{}); err != nil && c.err == nil {
+				c.err = err
+			}
+		}()
+	}
+
+	first, err := c.reader.Peek(1)
+	if err != nil {
+		c.missingHeader(err)
+
+		return
+	}
+
+	switch first[0] {
+	case proxyv1Prefix[0]:
+		if prefix, err := c.reader.Peek(len(proxyv1Prefix)); err != nil || !bytes.Equal(prefix, proxyv1Prefix) {
+			c.missingHeader(err)
+
+			return
+		}
+
+		c.raddr, c.laddr, c.err = readPROXYv1(c.reader)
+	case proxyv2Signature[0]:
+		if signature, err := c.reader.Peek(len(proxyv2Signature)); err != nil || !bytes.Equal(signature, proxyv2Signature) {
+			c.missingHeader(err)
+
+			return
+		}
+
+		c.raddr, c.laddr, c.err = readPROXYv2(c.reader)
+	default:
+		c.missingHeader(nil)
+	}
+}
+
+func (c *proxyProtocolConn) missingHeader(err error) {
+	if c.listener.Strict {
+		c.err = errMissingPROXYHeader
+
+		return
+	}
+
+	// Let the caller see the read error, e.g. if the client went away; timeouts only mean that no header was sent
+	if err != nil && err != io.EOF {
+		if timeout, ok := err.(interface{ Timeout() bool }); !ok || !timeout.Timeout() {
+			c.err = err
+		}
+	}
+}
+
+// Version 1: "PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n"
+func readPROXYv1(reader *bufio.Reader) (net.Addr, net.Addr, error) {
+	line, err := reader.ReadSlice('\n')
+	if err != nil {
+		return nil, nil, errInvalidPROXYHeader
+	}
+
+	if len(line) > maxPROXYv1HeaderLength || !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, nil, errInvalidPROXYHeader
+	}
+
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	if len(fields) < 2 {
+		return nil, nil, errInvalidPROXYHeader
+	}
+
+	switch fields[1] {
+	case "UNKNOWN":
+		return nil, nil, nil
+	case "TCP4", "TCP6":
+		if len(fields) != 6 {
+			return nil, nil, errInvalidPROXYHeader
+		}
+
+		raddr, err := parsePROXYv1Addr(fields[1], fields[2], fields[4])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		laddr, err := parsePROXYv1Addr(fields[1], fields[3], fields[5])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return raddr, laddr, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported PROXY protocol family %v", fields[1])
+	}
+}
+
+func parsePROXYv1Addr(family, rawIP, rawPort string) (*tinynet.TCPAddr, error) {
+	ip := net.ParseIP(rawIP)
+	if ip == nil || (family == "TCP4") != (ip.To4() != nil) {
+		return nil, errInvalidPROXYHeader
+	}
+
+	port, err := strconv.Atoi(rawPort)
+	if err != nil || port < 0 || port > 65535 {
+		return nil, errInvalidPROXYHeader
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	return &tinynet.TCPAddr{
+		IP:   tinynet.IP(ip),
+		Port: port,
+	}, nil
+}
+
+// Version 2: signature (12 bytes), version and command, family and transport, length (2 bytes), addresses and TLVs
+func readPROXYv2(reader *bufio.Reader) (net.Addr, net.Addr, error) {
+	header := make([]byte, proxyv2HeaderLength)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, nil, errInvalidPROXYHeader
+	}
+
+	if header[12]>>4 != 2 {
+		return nil, nil, fmt.Errorf("unsupported PROXY protocol version %v", header[12]>>4)
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, nil, errInvalidPROXYHeader
+	}
+
+	switch header[12] & 0xf {
+	case 0x0:
+		// LOCAL; the connection was made by the proxy itself
+		return nil, nil, nil
+	case 0x1:
+		// PROXY
+	default:
+		return nil, nil, errInvalidPROXYHeader
+	}
+
+	// Only streams carry TCP addresses; other transports keep the proxy's addresses
+	if header[13]&0xf != 0x1 {
+		return nil, nil, nil
+	}
+
+	switch header[13] >> 4 {
+	case 0x1:
+		if len(payload) < 12 {
+			return nil, nil, errInvalidPROXYHeader
+		}
+
+		return parsePROXYv2Addr(payload[0:4], payload[8:10]), parsePROXYv2Addr(payload[4:8], payload[10:12]), nil
+	case 0x2:
+		if len(payload) < 36 {
+			return nil, nil, errInvalidPROXYHeader
+		}
+
+		return parsePROXYv2Addr(payload[0:16], payload[32:34]), parsePROXYv2Addr(payload[16:32], payload[34:36]), nil
+	default:
+		return nil, nil, nil
+	}
+}
+
+func parsePROXYv2Addr(ip []byte, port []byte) *tinynet.TCPAddr {
+	return &tinynet.TCPAddr{
+		IP:   tinynet.IP(append([]byte{}, ip...)),
+		Port: int(binary.BigEndian.Uint16(port)),
+	}
+}
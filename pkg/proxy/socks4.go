@@ -0,0 +1,102 @@
+package proxy
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+const (
+	socks4Version = 0x04
+
+	socks4CmdConnect = 0x01
+
+	socks4Granted = 0x5a
+)
+
+var socks4Replies = map[byte]string{
+	0x5b: "request rejected or failed",
+	0x5c: "request rejected because the SOCKS server could not connect to identd",
+	0x5d: "request rejected because identd reported a different user ID",
+}
+
+type SOCKS4Dialer struct {
+	ProxyAddr string
+	UserID    string
+}
+
+func (d *SOCKS4Dialer) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := splitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		return nil, errors.New("could not use IPv6 address with SOCKS4")
+	}
+
+	conn, err := dialProxy(ctx, network, d.ProxyAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.handshake(conn, host, port); err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	if err := finishHandshake(conn); err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (d *SOCKS4Dialer) handshake(conn net.Conn, host string, port int) error {
+	req := []byte{socks4Version, socks4CmdConnect}
+
+	rawPort := make([]byte, 2)
+	binary.BigEndian.PutUint16(rawPort, uint16(port))
+	req = append(req, rawPort...)
+
+	// Numeric IPv4 addresses use SOCKS4, host names are passed through with SOCKS4a
+	if ip := net.ParseIP(host); ip != nil {
+		req = append(req, ip.To4()...)
+		req = append(append(req, d.UserID...), 0x00)
+	} else {
+		req = append(req, 0x00, 0x00, 0x00, 0x01)
+		req = append(append(req, d.UserID...), 0x00)
+		req = append(append(req, host...), 0x00)
+	}
+
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	// Read reply
+	reply := make([]byte, 8)
+	if _, err := tinynet.ReadFull(conn, reply); err != nil {
+		return err
+	}
+
+	if reply[0] != 0x00 {
+		return errors.New("could not connect via SOCKS4, unexpected reply version")
+	}
+
+	if reply[1] != socks4Granted {
+		if msg, ok := socks4Replies[reply[1]]; ok {
+			return fmt.Errorf("could not connect via SOCKS4: %v", msg)
+		}
+
+		return fmt.Errorf("could not connect via SOCKS4, unknown reply %v", reply[1])
+	}
+
+	return nil
+}
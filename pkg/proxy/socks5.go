@@ -0,0 +1,194 @@
+package proxy
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+const (
+	socks5Version = 0x05
+
+	socks5AuthNone     = 0x00
+	socks5AuthPassword = 0x02
+	socks5AuthNoAccept = 0xff
+
+	socks5CmdConnect = 0x01
+
+	socks5AtypIPv4   = 0x01
+	socks5AtypDomain = 0x03
+	socks5AtypIPv6   = 0x04
+)
+
+var socks5Replies = map[byte]string{
+	0x01: "general SOCKS server failure",
+	0x02: "connection not allowed by ruleset",
+	0x03: "network unreachable",
+	0x04: "host unreachable",
+	0x05: "connection refused",
+	0x06: "TTL expired",
+	0x07: "command not supported",
+	0x08: "address type not supported",
+}
+
+type SOCKS5Dialer struct {
+	ProxyAddr string
+	Username  string
+	Password  string
+}
+
+func (d *SOCKS5Dialer) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := splitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dialProxy(ctx, network, d.ProxyAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.handshake(conn, host, port); err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	if err := finishHandshake(conn); err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (d *SOCKS5Dialer) handshake(conn net.Conn, host string, port int) error {
+	// Negotiate authentication method
+	methods := []byte{socks5AuthNone}
+	if d.Username != "" {
+		methods = []byte{socks5AuthPassword, socks5AuthNone}
+	}
+
+	if _, err := conn.Write(append([]byte{socks5Version, byte(len(methods))}, methods...)); err != nil {
+		return err
+	}
+
+	res := make([]byte, 2)
+	if _, err := tinynet.ReadFull(conn, res); err != nil {
+		return err
+	}
+
+	if res[0] != socks5Version {
+		return errors.New("could not negotiate SOCKS5, unexpected version")
+	}
+
+	switch res[1] {
+	case socks5AuthNone:
+	case socks5AuthPassword:
+		if err := d.authenticate(conn); err != nil {
+			return err
+		}
+	case socks5AuthNoAccept:
+		return errors.New("could not negotiate SOCKS5 authentication method")
+	default:
+		return fmt.Errorf("could not use unsupported SOCKS5 authentication method %v", res[1])
+	}
+
+	// Connect
+	req := []byte{socks5Version, socks5CmdConnect, 0x00}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			req = append(append(req, socks5AtypIPv4), ip4...)
+		} else {
+			req = append(append(req, socks5AtypIPv6), ip.To16()...)
+		}
+	} else {
+		if len(host) > 255 {
+			return errors.New("could not use host name longer than 255 bytes")
+		}
+
+		req = append(append(req, socks5AtypDomain, byte(len(host))), host...)
+	}
+
+	rawPort := make([]byte, 2)
+	binary.BigEndian.PutUint16(rawPort, uint16(port))
+	req = append(req, rawPort...)
+
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	// Read reply
+	reply := make([]byte, 4)
+	if _, err := tinynet.ReadFull(conn, reply); err != nil {
+		return err
+	}
+
+	if reply[0] != socks5Version {
+		return errors.New("could not connect via SOCKS5, unexpected version")
+	}
+
+	if reply[1] != 0x00 {
+		if msg, ok := socks5Replies[reply[1]]; ok {
+			return fmt.Errorf("could not connect via SOCKS5: %v", msg)
+		}
+
+		return fmt.Errorf("could not connect via SOCKS5, unknown reply %v", reply[1])
+	}
+
+	// Discard the bound address
+	addrLen := 0
+	switch reply[3] {
+	case socks5AtypIPv4:
+		addrLen = net.IPv4len
+	case socks5AtypIPv6:
+		addrLen = net.IPv6len
+	case socks5AtypDomain:
+		rawLen := make([]byte, 1)
+		if _, err := tinynet.ReadFull(conn, rawLen); err != nil {
+			return err
+		}
+
+		addrLen = int(rawLen[0])
+	default:
+		return fmt.Errorf("could not parse SOCKS5 address type %v", reply[3])
+	}
+
+	if _, err := tinynet.ReadFull(conn, make([]byte, addrLen+2)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d *SOCKS5Dialer) authenticate(conn net.Conn) error {
+	if len(d.Username) > 255 || len(d.Password) > 255 {
+		return errors.New("could not use SOCKS5 credentials longer than 255 bytes")
+	}
+
+	// Username/password authentication (RFC 1929)
+	req := []byte{0x01, byte(len(d.Username))}
+	req = append(req, d.Username...)
+	req = append(req, byte(len(d.Password)))
+	req = append(req, d.Password...)
+
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	res := make([]byte, 2)
+	if _, err := tinynet.ReadFull(conn, res); err != nil {
+		return err
+	}
+
+	if res[1] != 0x00 {
+		return errors.New("could not authenticate with SOCKS5 proxy")
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package quic
+
+import (
+	"io"
+	"net"
+	"time"
+)
+
+// The subset of quic-go's quic.Stream which is needed to adapt it
+type Stream interface {
+	io.ReadWriteCloser
+
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+}
+
+type streamConn struct {
+	Stream
+
+	local  net.Addr
+	remote net.Addr
+}
+
+// Closing the conn closes the stream, which in quic-go only closes its write direction
+func AdaptStream(s Stream, local, remote net.Addr) net.Conn {
+	return &streamConn{
+		Stream: s,
+		local:  local,
+		remote: remote,
+	}
+}
+
+func (c *streamConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c *streamConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+func (c *streamConn) SetDeadline(t time.Time) error {
+	if err := c.Stream.SetReadDeadline(t); err != nil {
+		return err
+	}
+
+	return c.Stream.SetWriteDeadline(t)
+}
@@ -0,0 +1,148 @@
+package ratelimit
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+	lock   sync.Mutex
+}
+
+func newBucket(rate float64) *bucket {
+	// Allow up to one second worth of traffic at once by default
+	burst := int(rate)
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &bucket{
+		rate:   rate,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (b *bucket) unlimited() bool {
+	return b.rate <= 0
+}
+
+func (b *bucket) size() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.burst
+}
+
+func (b *bucket) setBurst(burst int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	b.burst = burst
+	if b.tokens > float64(burst) {
+		b.tokens = float64(burst)
+	}
+}
+
+func (b *bucket) wait(n int) {
+	if b.unlimited() {
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for {
+		// Refill
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > float64(b.burst) {
+			b.tokens = float64(b.burst)
+		}
+		b.last = now
+
+		if b.tokens >= float64(n) {
+			b.tokens -= float64(n)
+
+			return
+		}
+
+		// Sleep until enough tokens are available
+		missing := float64(n) - b.tokens
+		time.Sleep(time.Duration(missing / b.rate * float64(time.Second)))
+	}
+}
+
+type RateLimitedConn struct {
+	net.Conn
+
+	read  *bucket
+	write *bucket
+}
+
+func NewRateLimitedConn(inner net.Conn, readRate, writeRate float64) *RateLimitedConn {
+	return &RateLimitedConn{
+		Conn:  inner,
+		read:  newBucket(readRate),
+		write: newBucket(writeRate),
+	}
+}
+
+func (c *RateLimitedConn) SetBurst(readBurst, writeBurst int) {
+	c.read.setBurst(readBurst)
+	c.write.setBurst(writeBurst)
+}
+
+func (c *RateLimitedConn) Read(b []byte) (int, error) {
+	if c.read.unlimited() {
+		return c.Conn.Read(b)
+	}
+
+	// Never read more than the bucket can hold
+	if burst := c.read.size(); len(b) > burst {
+		b = b[:burst]
+	}
+
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.read.wait(n)
+	}
+
+	return n, err
+}
+
+func (c *RateLimitedConn) Write(b []byte) (int, error) {
+	if c.write.unlimited() {
+		return c.Conn.Write(b)
+	}
+
+	written := 0
+	for len(b) > 0 {
+		chunk := b
+		if burst := c.write.size(); len(chunk) > burst {
+			chunk = chunk[:burst]
+		}
+
+		c.write.wait(len(chunk))
+
+		n, err := c.Conn.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+
+		b = b[n:]
+	}
+
+	return written, nil
+}
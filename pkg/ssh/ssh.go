@@ -0,0 +1,66 @@
+package ssh
+
+import (
+	"net"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+	"golang.org/x/crypto/ssh"
+)
+
+func Dial(addr string, cfg *ssh.ClientConfig) (*ssh.Client, error) {
+	conn, err := tinynet.DialTimeout("tcp", addr, cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, cfg)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// The handler only gets the connection, so global requests are discarded and
+// channels are rejected; use Serve to handle them.
+func NewServer(addr string, cfg *ssh.ServerConfig, handler func(*ssh.ServerConn)) error {
+	l, err := tinynet.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	return Serve(l, cfg, func(conn *ssh.ServerConn, chans <-chan ssh.NewChannel, reqs <-chan *ssh.Request) {
+		go ssh.DiscardRequests(reqs)
+		go func() {
+			for newChannel := range chans {
+				_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			}
+		}()
+
+		handler(conn)
+	})
+}
+
+func Serve(l net.Listener, cfg *ssh.ServerConfig, handler func(*ssh.ServerConn, <-chan ssh.NewChannel, <-chan *ssh.Request)) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			// Handshake
+			serverConn, chans, reqs, err := ssh.NewServerConn(conn, cfg)
+			if err != nil {
+				_ = conn.Close()
+
+				return
+			}
+
+			handler(serverConn, chans, reqs)
+		}()
+	}
+}
@@ -0,0 +1,131 @@
+package testing
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	acceptBacklog = 128
+)
+
+var (
+	errListenerClosed = errors.New("could not accept on closed listener")
+)
+
+type inProcessAddr string
+
+func (a inProcessAddr) Network() string {
+	return "inprocess"
+}
+
+func (a inProcessAddr) String() string {
+	return string(a)
+}
+
+type InProcessTransport struct {
+	listeners map[string]*inProcessListener
+	nextPort  uint64
+	lock      sync.Mutex
+}
+
+func NewInProcessTransport() *InProcessTransport {
+	return &InProcessTransport{
+		listeners: map[string]*inProcessListener{},
+	}
+}
+
+func (t *InProcessTransport) Listen(addr string) (net.Listener, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.listeners[addr]; ok {
+		return nil, fmt.Errorf("could not listen on %v: address already in use", addr)
+	}
+
+	l := &inProcessListener{
+		transport: t,
+		addr:      inProcessAddr(addr),
+		conns:     make(chan net.Conn, acceptBacklog),
+		closed:    make(chan struct{}),
+	}
+
+	t.listeners[addr] = l
+
+	return l, nil
+}
+
+func (t *InProcessTransport) Dial(addr string) (net.Conn, error) {
+	t.lock.Lock()
+	l, ok := t.listeners[addr]
+	t.lock.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("could not dial %v: connection refused", addr)
+	}
+
+	// Give each client a unique address, like an ephemeral port
+	laddr := inProcessAddr(fmt.Sprintf("client-%v", atomic.AddUint64(&t.nextPort, 1)))
+
+	client, server := net.Pipe()
+
+	select {
+	case l.conns <- &inProcessConn{server, l.addr, laddr}:
+	case <-l.closed:
+		return nil, fmt.Errorf("could not dial %v: connection refused", addr)
+	}
+
+	return &inProcessConn{client, laddr, l.addr}, nil
+}
+
+type inProcessListener struct {
+	transport *InProcessTransport
+	addr      inProcessAddr
+
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (l *inProcessListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, errListenerClosed
+	}
+}
+
+func (l *inProcessListener) Close() error {
+	l.closeOnce.Do(func() {
+		l.transport.lock.Lock()
+		delete(l.transport.listeners, string(l.addr))
+		l.transport.lock.Unlock()
+
+		close(l.closed)
+	})
+
+	return nil
+}
+
+func (l *inProcessListener) Addr() net.Addr {
+	return l.addr
+}
+
+type inProcessConn struct {
+	net.Conn
+
+	laddr net.Addr
+	raddr net.Addr
+}
+
+func (c *inProcessConn) LocalAddr() net.Addr {
+	return c.laddr
+}
+
+func (c *inProcessConn) RemoteAddr() net.Addr {
+	return c.raddr
+}
@@ -0,0 +1,62 @@
+package tinynet
+
+import (
+	"errors"
+	"net"
+)
+
+type adaptedListener struct {
+	l *TCPListener
+}
+
+func AdaptListener(l *TCPListener) net.Listener {
+	return &adaptedListener{l}
+}
+
+func (a *adaptedListener) Accept() (net.Conn, error) {
+	return a.AcceptTCP()
+}
+
+func (a *adaptedListener) AcceptTCP() (*net.TCPConn, error) {
+	conn, err := a.l.AcceptTCP()
+	if err != nil {
+		return nil, err
+	}
+
+	// Hand the socket over to the runtime's network poller
+	f, err := conn.File()
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+	defer f.Close()
+
+	// The duplicated descriptor keeps the socket alive, so don't shut it down
+	conn.untrack()
+	if err := closeSocket(conn.fd); err != nil {
+		return nil, err
+	}
+
+	stdConn, err := net.FileConn(f)
+	if err != nil {
+		return nil, err
+	}
+
+	tcpConn, ok := stdConn.(*net.TCPConn)
+	if !ok {
+		_ = stdConn.Close()
+
+		return nil, errors.New("could not adapt non-TCP connection")
+	}
+
+	return tcpConn, nil
+}
+
+func (a *adaptedListener) Close() error {
+	return a.l.Close()
+}
+
+func (a *adaptedListener) Addr() net.Addr {
+	return a.l.Addr()
+}
@@ -0,0 +1,147 @@
+package tinynet
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	MaxAddrRangeLength = 1 << 16
+)
+
+// Splits "host:port", "[host]:port" and plain hosts, which get port 0
+func splitOptionalPort(s string) (string, int, error) {
+	host, rawPort, err := net.SplitHostPort(s)
+	if err != nil {
+		// IPv6 addresses without a port contain colons too, so only give up if there is a port
+		if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+			return s[1 : len(s)-1], 0, nil
+		}
+
+		if strings.Count(s, ":") != 1 {
+			return s, 0, nil
+		}
+
+		return "", 0, errors.New("could not parse address")
+	}
+
+	port, err := strconv.Atoi(rawPort)
+	if err != nil || port < 0 || port > 65535 {
+		return "", 0, errors.New("could not parse port")
+	}
+
+	return host, port, nil
+}
+
+func parseWildcardIP(host string) (IP, string, error) {
+	// Wildcards match all addresses
+	switch host {
+	case "*", "0.0.0.0":
+		return IP{0, 0, 0, 0}, "", nil
+	case "::":
+		return IP(net.IPv6unspecified), "", nil
+	}
+
+	return parseIP(host)
+}
+
+func ParseAddr(s string) (*TCPAddr, error) {
+	host, port, err := splitOptionalPort(s)
+	if err != nil {
+		return nil, err
+	}
+
+	ip, zone, err := parseWildcardIP(host)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TCPAddr{
+		IP:   ip,
+		Port: port,
+		Zone: zone,
+	}, nil
+}
+
+// CIDR blocks may have a port, e.g. "10.0.0.0/8:80" or "[2001:db8::/32]:80"
+func ParseCIDR(s string) (*net.IPNet, *TCPAddr, error) {
+	host, port, err := splitOptionalPort(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ip, network, err := net.ParseCIDR(host)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not parse CIDR block: %v", err)
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	return network, &TCPAddr{
+		IP:   IP(ip),
+		Port: port,
+	}, nil
+}
+
+// Ranges are inclusive, e.g. "192.168.1.1-192.168.1.10:8080", "192.168.1.1-10:8080" or "[2001:db8::1-2001:db8::ff]:80"
+func ParseAddrRange(s string) ([]TCPAddr, error) {
+	host, port, err := splitOptionalPort(s)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(host, "-", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("could not parse address range")
+	}
+
+	first, _, err := parseIP(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	rawLast := parts[1]
+
+	// Only the last octet is given for short IPv4 ranges
+	if !isIPv6(first) && !strings.Contains(rawLast, ".") {
+		rawLast = fmt.Sprintf("%v.%v.%v.%v", first[0], first[1], first[2], rawLast)
+	}
+
+	last, _, err := parseIP(rawLast)
+	if err != nil {
+		return nil, err
+	}
+
+	if isIPv6(first) != isIPv6(last) {
+		return nil, errors.New("could not parse address range with mixed address families")
+	}
+
+	start, end := new(big.Int).SetBytes(first), new(big.Int).SetBytes(last)
+	if start.Cmp(end) > 0 {
+		return nil, errors.New("could not parse address range which ends before it starts")
+	}
+
+	length := new(big.Int).Sub(end, start)
+	if length.Cmp(big.NewInt(MaxAddrRangeLength)) >= 0 {
+		return nil, fmt.Errorf("could not parse address range longer than %v addresses", MaxAddrRangeLength)
+	}
+
+	addrs := make([]TCPAddr, 0, length.Int64()+1)
+	for current := start; current.Cmp(end) <= 0; current = new(big.Int).Add(current, big.NewInt(1)) {
+		ip := make(IP, len(first))
+		current.FillBytes(ip)
+
+		addrs = append(addrs, TCPAddr{
+			IP:   ip,
+			Port: port,
+		})
+	}
+
+	return addrs, nil
+}
@@ -0,0 +1,53 @@
+package tinynet
+
+import (
+	"bufio"
+	"io"
+	"net"
+)
+
+type BufferedConn struct {
+	net.Conn
+
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+func NewBufferedConn(c net.Conn, readSize, writeSize int) *BufferedConn {
+	return &BufferedConn{
+		Conn: c,
+
+		reader: bufio.NewReaderSize(c, readSize),
+		writer: bufio.NewWriterSize(c, writeSize),
+	}
+}
+
+func (c *BufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+func (c *BufferedConn) Write(b []byte) (int, error) {
+	return c.writer.Write(b)
+}
+
+func (c *BufferedConn) Flush() error {
+	return c.writer.Flush()
+}
+
+func (c *BufferedConn) ReadFrom(r io.Reader) (int64, error) {
+	return c.writer.ReadFrom(r)
+}
+
+func (c *BufferedConn) WriteTo(w io.Writer) (int64, error) {
+	return c.reader.WriteTo(w)
+}
+
+func (c *BufferedConn) Close() error {
+	flushErr := c.writer.Flush()
+
+	if err := c.Conn.Close(); err != nil {
+		return err
+	}
+
+	return flushErr
+}
@@ -0,0 +1,423 @@
+package tinynet
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/alphahorizonio/unisockets/pkg/unisockets"
+)
+
+const (
+	DefaultBacklog         = 128
+	DefaultKeepAlivePeriod = 15 * time.Second
+	DefaultKeepAliveCount  = 9
+
+	DefaultFastOpenQueueLength = 256
+)
+
+type ListenConfig struct {
+	Backlog      int
+	ReuseAddr    bool
+	ReusePort    bool
+	BindToDevice string
+	Transparent  bool
+	FastOpen     bool
+	Mark         uint32
+	Logger       Logger
+}
+
+func NewListenConfig() *ListenConfig {
+	return &ListenConfig{
+		Backlog:   DefaultBacklog,
+		ReuseAddr: true,
+	}
+}
+
+func (lc *ListenConfig) Listen(ctx context.Context, network, address string) (net.Listener, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+		laddr, err := ResolveTCPAddr(network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		return lc.listenTCP(network, laddr)
+	case "unix":
+		laddr, err := ResolveUnixAddr(network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		return lc.listenUnix(network, laddr)
+	case "sctp":
+		laddr, err := ResolveSCTPAddr(network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		return ListenSCTP(laddr)
+	default:
+		return nil, fmt.Errorf("unsupported network %v", network)
+	}
+}
+
+func (lc *ListenConfig) listenTCP(network string, laddr *TCPAddr) (*TCPListener, error) {
+	if err := checkTCPNetwork(network); err != nil {
+		return nil, err
+	}
+
+	ip, err := ipForNetwork(network, laddr.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ip.Equal(laddr.IP) {
+		laddr = &TCPAddr{
+			stringAddr: laddr.stringAddr,
+			IP:         ip,
+			Port:       laddr.Port,
+			Zone:       laddr.Zone,
+		}
+	}
+
+	backlog := lc.Backlog
+	if backlog <= 0 {
+		backlog = DefaultBacklog
+	}
+
+	// Create socket
+	serverSocket, err := newSocket(laddr.IP, unisockets.SOCK_STREAM)
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't leak the socket if any of the following steps fail
+	listening := false
+	defer func() {
+		if !listening {
+			_ = closeSocket(serverSocket)
+		}
+	}()
+
+	// Set socket options
+	if lc.Mark != 0 {
+		if err := setMark(serverSocket, lc.Mark); err != nil {
+			return nil, err
+		}
+	}
+
+	if lc.ReuseAddr {
+		if err := setReuseAddr(serverSocket, true); err != nil {
+			return nil, err
+		}
+	}
+
+	if lc.ReusePort {
+		if err := setReusePort(serverSocket, true); err != nil {
+			return nil, err
+		}
+	}
+
+	if lc.BindToDevice != "" {
+		if err := bindToDevice(serverSocket, lc.BindToDevice); err != nil {
+			return nil, err
+		}
+	}
+
+	if lc.Transparent {
+		if err := setTransparent(serverSocket, true, isIPv6(laddr.IP)); err != nil {
+			return nil, err
+		}
+	}
+
+	if lc.FastOpen {
+		if err := setFastOpen(serverSocket, true); err != nil {
+			return nil, err
+		}
+	}
+
+	// Bind
+	if err := bindSocket(serverSocket, laddr.IP, laddr.Port, laddr.Zone); err != nil {
+		return nil, err
+	}
+
+	// Listen
+	if err := unisockets.Listen(serverSocket, int32(backlog)); err != nil {
+		return nil, err
+	}
+
+	listening = true
+
+	// Report the port the system picked for ":0"
+	var addr net.Addr = laddr
+	if laddr.Port == 0 {
+		addr = lookupTCPAddr(laddr, serverSocket, getsockname)
+	}
+
+	l := &TCPListener{
+		fd:      serverSocket,
+		network: network,
+		addr:    addr,
+		closed:  new(int32),
+		tracker: NewConnectionTracker(),
+		logger:  lc.Logger,
+	}
+
+	l.log().Info("listening", "network", network, "addr", addr)
+
+	return l, nil
+}
+
+// Zero values use the defaults; negative values keep the system's settings
+type KeepAliveConfig struct {
+	Enable   bool
+	Idle     time.Duration
+	Interval time.Duration
+	Count    int
+}
+
+type DialConfig struct {
+	Timeout         time.Duration
+	KeepAlive       bool
+	KeepAlivePeriod time.Duration
+	NoDelay         bool
+	BindToDevice    string
+	Mark            uint32
+	Logger          Logger
+
+	control      func(fd int32) error
+	fastOpenData []byte
+}
+
+func NewDialConfig() *DialConfig {
+	return &DialConfig{
+		KeepAlive:       true,
+		KeepAlivePeriod: DefaultKeepAlivePeriod,
+		NoDelay:         true,
+	}
+}
+
+func (dc *DialConfig) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	return dc.dial(ctx, network, "", address)
+}
+
+func (dc *DialConfig) dial(ctx context.Context, network, localAddress, address string) (net.Conn, error) {
+	if dc.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, dc.Timeout)
+		defer cancel()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+		var laddr *TCPAddr
+		if localAddress != "" {
+			var err error
+			laddr, err = ResolveTCPAddr(network, localAddress)
+			if err != nil {
+				return TCPConn{}, err
+			}
+		}
+
+		raddr, err := ResolveTCPAddr(network, address)
+		if err != nil {
+			return TCPConn{}, err
+		}
+
+		conn, err := dc.dialTCP(ctx, network, laddr, raddr)
+		if err != nil {
+			return TCPConn{}, err
+		}
+
+		return *conn, err
+	case "udp", "udp4", "udp6":
+		var laddr *UDPAddr
+		if localAddress != "" {
+			var err error
+			laddr, err = ResolveUDPAddr(network, localAddress)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		raddr, err := ResolveUDPAddr(network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		return DialUDP(network, laddr, raddr)
+	case "unix":
+		var laddr *UnixAddr
+		if localAddress != "" {
+			var err error
+			laddr, err = ResolveUnixAddr(network, localAddress)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		raddr, err := ResolveUnixAddr(network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		return DialUnix(network, laddr, raddr)
+	case "sctp":
+		var laddr *SCTPAddr
+		if localAddress != "" {
+			var err error
+			laddr, err = ResolveSCTPAddr(network, localAddress)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		raddr, err := ResolveSCTPAddr(network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		return DialSCTP(laddr, raddr)
+	default:
+		return nil, fmt.Errorf("unsupported network %v", network)
+	}
+}
+
+func (dc *DialConfig) log() Logger {
+	if dc.Logger != nil {
+		return dc.Logger
+	}
+
+	return getLogger()
+}
+
+func (dc *DialConfig) dialTCP(ctx context.Context, network string, laddr, raddr *TCPAddr) (*TCPConn, error) {
+	conn, err := dc.connectTCP(ctx, network, laddr, raddr)
+	if err != nil {
+		dc.log().Error("could not dial", "network", network, "raddr", raddr, "err", err)
+
+		return nil, err
+	}
+
+	conn.log().Debug("dialed connection", "network", network, "raddr", raddr)
+
+	return conn, nil
+}
+
+func (dc *DialConfig) connectTCP(ctx context.Context, network string, laddr, raddr *TCPAddr) (*TCPConn, error) {
+	if err := checkTCPNetwork(network); err != nil {
+		return nil, err
+	}
+
+	if _, err := ipForNetwork(network, raddr.IP); err != nil {
+		return nil, err
+	}
+
+	// Create socket
+	serverSocket, err := newSocket(raddr.IP, unisockets.SOCK_STREAM)
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't leak the socket if any of the following steps fail
+	dialed := false
+	defer func() {
+		if !dialed {
+			_ = closeSocket(serverSocket)
+		}
+	}()
+
+	// Set socket options
+	if dc.Mark != 0 {
+		if err := setMark(serverSocket, dc.Mark); err != nil {
+			return nil, err
+		}
+	}
+
+	if dc.BindToDevice != "" {
+		if err := bindToDevice(serverSocket, dc.BindToDevice); err != nil {
+			return nil, err
+		}
+	}
+
+	if dc.control != nil {
+		if err := dc.control(serverSocket); err != nil {
+			return nil, err
+		}
+	}
+
+	// Bind
+	if laddr != nil {
+		if err := bindSocket(serverSocket, laddr.IP, laddr.Port, laddr.Zone); err != nil {
+			return nil, err
+		}
+	}
+
+	// Connect
+	if dc.fastOpenData != nil {
+		if err := sendFastOpen(serverSocket, dc.fastOpenData, raddr.IP, raddr.Port, raddr.Zone); err != nil {
+			if isTimeout(err) {
+				return nil, os.ErrDeadlineExceeded
+			}
+
+			return nil, err
+		}
+	} else if err := connectContext(ctx, serverSocket, raddr.IP, raddr.Port, raddr.Zone); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if isTimeout(err) {
+			return nil, os.ErrDeadlineExceeded
+		}
+
+		return nil, err
+	}
+
+	// Set socket options
+	if dc.NoDelay {
+		if err := setNoDelay(serverSocket, true); err != nil {
+			return nil, err
+		}
+	}
+
+	if dc.KeepAlive {
+		if err := setKeepAlive(serverSocket, true); err != nil {
+			return nil, err
+		}
+
+		if dc.KeepAlivePeriod > 0 {
+			if err := setKeepAlivePeriod(serverSocket, dc.KeepAlivePeriod); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	conn := &TCPConn{
+		fd:      serverSocket,
+		network: network,
+		raddr:   raddr,
+		state: &connState{
+			logger: dc.Logger,
+		},
+	}
+
+	if laddr != nil {
+		conn.laddr = laddr
+	}
+
+	dialed = true
+
+	return conn, nil
+}
@@ -0,0 +1,83 @@
+package tinynet
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestListenerAddr(t *testing.T) {
+	l, err := Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	addr, ok := l.Addr().(*TCPAddr)
+	if !ok {
+		t.Fatalf("got address of type %T, expected *TCPAddr", l.Addr())
+	}
+
+	if addr.Port == 0 {
+		t.Fatal("listener reported port 0")
+	}
+
+	// The reported address must be dialable
+	conn, err := net.Dial("tcp", addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = conn.Close()
+}
+
+func TestReusePort(t *testing.T) {
+	lc := &ListenConfig{ReusePort: true}
+
+	first, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+
+	addr := first.Addr().String()
+
+	second, err := lc.Listen(context.Background(), "tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+
+	accepted := make(chan int)
+	for i, l := range []net.Listener{first, second} {
+		go func(i int, l net.Listener) {
+			for {
+				conn, err := l.Accept()
+				if err != nil {
+					return
+				}
+
+				_ = conn.Close()
+
+				accepted <- i
+			}
+		}(i, l)
+	}
+
+	// The kernel hashes connections across the listeners, so both should see some of them
+	seen := map[int]bool{}
+	for i := 0; i < 100 && len(seen) < 2; i++ {
+		conn, err := Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		seen[<-accepted] = true
+
+		_ = conn.Close()
+	}
+
+	if len(seen) < 2 {
+		t.Fatal("only one listener accepted connections")
+	}
+}
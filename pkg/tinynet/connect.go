@@ -0,0 +1,64 @@
+package tinynet
+
+import (
+	"context"
+	"time"
+)
+
+func connectContext(ctx context.Context, fd int32, ip IP, port int, zone string) error {
+	// Connect can't be interrupted without polling, so fall back to a blocking connect with a send timeout
+	if ctx.Done() == nil || !canPoll {
+		if deadline, ok := ctx.Deadline(); ok {
+			if err := setWriteTimeout(fd, deadline); err != nil {
+				return err
+			}
+
+			defer setWriteTimeout(fd, time.Time{})
+		}
+
+		return connectSocket(fd, ip, port, zone)
+	}
+
+	if err := setNonblock(fd, true); err != nil {
+		return err
+	}
+
+	if err := connectSocket(fd, ip, port, zone); err != nil {
+		if !isInProgress(err) {
+			return err
+		}
+
+		// Wait for the connection to be established or the context to be done
+		for {
+			timeout := DefaultPollInterval
+			if deadline, ok := ctx.Deadline(); ok {
+				if remaining := time.Until(deadline); remaining < timeout {
+					timeout = remaining
+				}
+			}
+
+			if timeout < 0 {
+				timeout = 0
+			}
+
+			ready, err := waitWritable(fd, timeout)
+			if err != nil {
+				return err
+			}
+
+			if ready {
+				if err := socketError(fd); err != nil {
+					return err
+				}
+
+				break
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return setNonblock(fd, false)
+}
@@ -0,0 +1,138 @@
+package tinynet
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+)
+
+func waitContext(ctx context.Context, fd int32, wait func(fd int32, timeout time.Duration) (bool, error)) error {
+	for {
+		timeout := DefaultPollInterval
+		if deadline, ok := ctx.Deadline(); ok {
+			if remaining := time.Until(deadline); remaining < timeout {
+				timeout = remaining
+			}
+		}
+
+		if timeout < 0 {
+			timeout = 0
+		}
+
+		ready, err := wait(fd, timeout)
+		if err != nil {
+			return err
+		}
+
+		if ready {
+			return nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// Moves the deadline into the past once ctx is done, so that blocked calls return.
+// The returned function stops watching ctx and restores the deadline if it was changed.
+func interruptContext(ctx context.Context, setDeadline func(t time.Time) error) func() {
+	deadline, hasDeadline := ctx.Deadline()
+	if hasDeadline {
+		_ = setDeadline(deadline)
+	}
+
+	if ctx.Done() == nil {
+		return func() {
+			if hasDeadline {
+				_ = setDeadline(time.Time{})
+			}
+		}
+	}
+
+	stop := make(chan struct{})
+	interrupted := make(chan bool, 1)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = setDeadline(time.Now())
+
+			interrupted <- true
+		case <-stop:
+			interrupted <- false
+		}
+	}()
+
+	return func() {
+		close(stop)
+
+		if <-interrupted || hasDeadline {
+			_ = setDeadline(time.Time{})
+		}
+	}
+}
+
+// Reports timeouts caused by ctx as ctx's error
+func contextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if ctxErr := ctx.Err(); ctxErr != nil && (errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, ctxErr)) {
+		return wrapError(ctxErr)
+	}
+
+	return wrapError(err)
+}
+
+func (c TCPConn) ReadContext(ctx context.Context, b []byte) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, wrapError(err)
+	}
+
+	stop := interruptContext(ctx, c.SetReadDeadline)
+	defer stop()
+
+	// Only read once data is available, so that the read doesn't block
+	if canPoll && ctx.Done() != nil {
+		if err := waitContext(ctx, c.fd, waitReadable); err != nil {
+			return 0, contextError(ctx, err)
+		}
+	}
+
+	n, err := c.Read(b)
+
+	return n, contextError(ctx, err)
+}
+
+func (c TCPConn) WriteContext(ctx context.Context, b []byte) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, wrapError(err)
+	}
+
+	stop := interruptContext(ctx, c.SetWriteDeadline)
+	defer stop()
+
+	if !canPoll || ctx.Done() == nil {
+		n, err := c.Write(b)
+
+		return n, contextError(ctx, err)
+	}
+
+	// Large writes could block until the peer reads, so only write what fits into the send buffer
+	written := 0
+	for written < len(b) {
+		if err := waitContext(ctx, c.fd, waitWritable); err != nil {
+			return written, contextError(ctx, err)
+		}
+
+		n, err := c.TryWrite(b[written:])
+		written += n
+		if err != nil && !errors.Is(err, errWouldBlock) {
+			return written, contextError(ctx, err)
+		}
+	}
+
+	return written, nil
+}
@@ -0,0 +1,101 @@
+package tinynet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// The accepted side doesn't read or write until the test does so
+func dialPeer(t *testing.T) (TCPConn, net.Conn) {
+	t.Helper()
+
+	l, addr := listenLoopback(t)
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			close(accepted)
+
+			return
+		}
+
+		accepted <- conn
+	}()
+
+	conn := dialLoopback(t, addr)
+
+	peer, ok := <-accepted
+	if !ok {
+		t.Fatal("could not accept")
+	}
+	t.Cleanup(func() {
+		_ = peer.Close()
+	})
+
+	return conn.(TCPConn), peer
+}
+
+func cancelAfter(d time.Duration) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(d, cancel)
+
+	return ctx
+}
+
+func TestReadContextCancel(t *testing.T) {
+	conn, peer := dialPeer(t)
+
+	start := time.Now()
+	if _, err := conn.ReadContext(cancelAfter(100*time.Millisecond), make([]byte, 1)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, expected %v", err, context.Canceled)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("read returned after %v", elapsed)
+	}
+
+	// The deadline set on cancellation must not affect later reads
+	time.AfterFunc(100*time.Millisecond, func() {
+		_, _ = peer.Write([]byte{1})
+	})
+
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteContextCancel(t *testing.T) {
+	conn, _ := dialPeer(t)
+
+	// Larger than the socket buffers, so that the write can't complete while the peer doesn't read
+	b := make([]byte, 64*1024*1024)
+
+	start := time.Now()
+	n, err := conn.WriteContext(cancelAfter(100*time.Millisecond), b)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, expected %v", err, context.Canceled)
+	}
+
+	if n >= len(b) {
+		t.Fatalf("wrote %v bytes, expected a partial write", n)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("write returned after %v", elapsed)
+	}
+}
+
+func TestReadContextDeadline(t *testing.T) {
+	conn, _ := dialPeer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err := conn.ReadContext(ctx, make([]byte, 1)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, expected %v", err, context.DeadlineExceeded)
+	}
+}
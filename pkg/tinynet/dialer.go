@@ -0,0 +1,91 @@
+package tinynet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+	"time"
+)
+
+type Dialer struct {
+	Timeout   time.Duration
+	KeepAlive time.Duration // Zero uses DefaultKeepAlivePeriod, negative disables keep-alives
+	LocalAddr net.Addr
+	Resolver  *Resolver
+	Control   func(network, address string, c syscall.RawConn) error
+}
+
+func (d *Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if err := checkTCPNetwork(network); err != nil {
+		return nil, err
+	}
+
+	if d.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.Timeout)
+		defer cancel()
+	}
+
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = DefaultResolver
+	}
+
+	raddr, err := resolver.ResolveTCPAddr(ctx, network, address)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	laddr, err := toTCPAddr(d.LocalAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	dc := NewDialConfig()
+	switch {
+	case d.KeepAlive < 0:
+		dc.KeepAlive = false
+	case d.KeepAlive > 0:
+		dc.KeepAlivePeriod = d.KeepAlive
+	}
+
+	if d.Control != nil {
+		dc.control = func(fd int32) error {
+			return d.Control(network, address, rawConn{fd})
+		}
+	}
+
+	conn, err := dc.dialTCP(ctx, network, laddr, raddr)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return *conn, nil
+}
+
+func toTCPAddr(addr net.Addr) (*TCPAddr, error) {
+	switch a := addr.(type) {
+	case nil:
+		return nil, nil
+	case *TCPAddr:
+		return a, nil
+	case *net.TCPAddr:
+		if a == nil {
+			return nil, nil
+		}
+
+		return &TCPAddr{
+			stringAddr: "",
+			IP:         IP(a.IP),
+			Port:       a.Port,
+			Zone:       a.Zone,
+		}, nil
+	default:
+		return nil, errors.New("could not use non-TCP local address")
+	}
+}
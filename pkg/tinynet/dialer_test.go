@@ -0,0 +1,21 @@
+package tinynet
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDialerWithRetryDialer(t *testing.T) {
+	addr := startEchoServer(t)
+
+	d := NewRetryDialer(ContextDialerFunc((&Dialer{}).DialContext), RetryOptions{})
+
+	conn, err := d.Dial(context.Background(), "tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
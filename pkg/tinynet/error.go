@@ -0,0 +1,60 @@
+package tinynet
+
+import (
+	"errors"
+	"os"
+)
+
+type Error struct {
+	Err         error
+	IsTimeout   bool
+	IsTemporary bool
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Timeout() bool {
+	return e.IsTimeout
+}
+
+func (e *Error) Temporary() bool {
+	return e.IsTemporary
+}
+
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// Don't wrap twice
+	if _, ok := err.(*Error); ok {
+		return err
+	}
+
+	wrapped := &Error{
+		Err:         err,
+		IsTimeout:   isTimeout(err) || errors.Is(err, os.ErrDeadlineExceeded),
+		IsTemporary: false,
+	}
+
+	if timeout, ok := err.(interface{ Timeout() bool }); ok && timeout.Timeout() {
+		wrapped.IsTimeout = true
+	}
+
+	if temporary, ok := err.(interface{ Temporary() bool }); ok && temporary.Temporary() {
+		wrapped.IsTemporary = true
+	}
+
+	// Timeouts can always be retried
+	if wrapped.IsTimeout {
+		wrapped.IsTemporary = true
+	}
+
+	return wrapped
+}
@@ -0,0 +1,59 @@
+package tinynet
+
+import (
+	"context"
+	"testing"
+)
+
+func benchmarkFirstResponse(b *testing.B, dial func(raddr *TCPAddr, msg []byte) (*TCPConn, error)) {
+	l, err := (&ListenConfig{FastOpen: true}).Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Skip("could not listen with fast open:", err)
+	}
+	defer l.Close()
+
+	go serveEcho(l)
+
+	raddr := l.Addr().(*TCPAddr)
+	msg := []byte{1}
+	buf := make([]byte, 1)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		conn, err := dial(raddr, msg)
+		if err != nil {
+			b.Fatal("could not dial:", err)
+		}
+
+		if _, err := ReadFull(conn, buf); err != nil {
+			b.Fatal("could not read:", err)
+		}
+
+		_ = conn.Close()
+	}
+}
+
+// The first connection fetches the cookie; later ones only save a round trip if the system allows fast open for servers
+func BenchmarkFastOpen(b *testing.B) {
+	benchmarkFirstResponse(b, func(raddr *TCPAddr, msg []byte) (*TCPConn, error) {
+		return DialTCPWithFastOpen("tcp", nil, raddr, msg)
+	})
+}
+
+func BenchmarkFastOpenDisabled(b *testing.B) {
+	benchmarkFirstResponse(b, func(raddr *TCPAddr, msg []byte) (*TCPConn, error) {
+		conn, err := DialTCP("tcp", nil, raddr)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := conn.Write(msg); err != nil {
+			_ = conn.Close()
+
+			return nil, err
+		}
+
+		return conn, nil
+	})
+}
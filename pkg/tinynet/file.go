@@ -0,0 +1,77 @@
+package tinynet
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+type rawConn struct {
+	fd int32
+}
+
+func (c rawConn) Control(f func(fd uintptr)) error {
+	f(uintptr(c.fd))
+
+	return nil
+}
+
+func (c rawConn) Read(f func(fd uintptr) bool) error {
+	// f is retried until it reports completion, waiting for the socket to become ready in between
+	for !f(uintptr(c.fd)) {
+		if err := c.wait(waitReadable); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c rawConn) Write(f func(fd uintptr) bool) error {
+	for !f(uintptr(c.fd)) {
+		if err := c.wait(waitWritable); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c rawConn) wait(wait func(fd int32, timeout time.Duration) (bool, error)) error {
+	// There is nothing to wait on without poll, so back off instead of spinning
+	if !canPoll {
+		time.Sleep(DefaultPollInterval)
+
+		return nil
+	}
+
+	// A negative timeout blocks until the socket is ready
+	_, err := wait(c.fd, -1)
+
+	return err
+}
+
+func newFile(fd int32, name string) (*os.File, error) {
+	nfd, err := dupSocket(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.NewFile(uintptr(nfd), name), nil
+}
+
+func (c TCPConn) File() (*os.File, error) {
+	return newFile(c.fd, "tcp:"+addrString(c.laddr)+"->"+addrString(c.raddr))
+}
+
+func (c TCPConn) SyscallConn() (syscall.RawConn, error) {
+	return rawConn{c.fd}, nil
+}
+
+func (t TCPListener) File() (*os.File, error) {
+	return newFile(t.fd, "tcp:"+addrString(t.addr)+"->")
+}
+
+func (t TCPListener) SyscallConn() (syscall.RawConn, error) {
+	return rawConn{t.fd}, nil
+}
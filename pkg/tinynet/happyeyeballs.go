@@ -0,0 +1,143 @@
+package tinynet
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	DefaultFallbackDelay = 250 * time.Millisecond
+)
+
+type dialResult struct {
+	conn *TCPConn
+	err  error
+}
+
+func DialHappyEyeballs(ctx context.Context, network, host string, port int) (net.Conn, error) {
+	if network != "tcp" {
+		return nil, fmt.Errorf("unsupported network %v", network)
+	}
+
+	// Resolve
+	ips := []IP{}
+	if ip, _, err := parseIP(host); err == nil {
+		ips = append(ips, ip)
+	} else {
+		candidates, err := DefaultResolver.lookupIPs(ctx, host)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+
+		ips = sortAddrs(candidates)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Buffered so that losing attempts never block
+	results := make(chan dialResult, len(ips))
+
+	dc := NewDialConfig()
+	next := 0
+	pending := 0
+	start := func() {
+		raddr := &TCPAddr{
+			IP:   ips[next],
+			Port: port,
+		}
+
+		next++
+		pending++
+
+		go func() {
+			conn, err := dc.dialTCP(ctx, network, nil, raddr)
+
+			results <- dialResult{conn, err}
+		}()
+	}
+
+	start()
+
+	timer := time.NewTimer(DefaultFallbackDelay)
+	defer timer.Stop()
+
+	var firstErr error
+	for pending > 0 {
+		select {
+		case res := <-results:
+			pending--
+
+			if res.err == nil {
+				cancel()
+
+				// Close connections of attempts which completed after the winner
+				go func(remaining int) {
+					for i := 0; i < remaining; i++ {
+						if loser := <-results; loser.err == nil {
+							_ = loser.conn.Close()
+						}
+					}
+				}(pending)
+
+				return *res.conn, nil
+			}
+
+			if firstErr == nil {
+				firstErr = res.err
+			}
+
+			// Don't wait for the stagger if an attempt failed
+			if next < len(ips) {
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+
+				start()
+				timer.Reset(DefaultFallbackDelay)
+			}
+		case <-timer.C:
+			if next < len(ips) {
+				start()
+				timer.Reset(DefaultFallbackDelay)
+			}
+		}
+	}
+
+	if err := ctx.Err(); err != nil && firstErr == nil {
+		return nil, wrapError(err)
+	}
+
+	return nil, wrapError(firstErr)
+}
+
+func sortAddrs(candidates []net.IP) []IP {
+	v4 := []IP{}
+	v6 := []IP{}
+	for _, candidate := range candidates {
+		if ip4 := candidate.To4(); ip4 != nil {
+			v4 = append(v4, IP(ip4))
+		} else {
+			v6 = append(v6, IP(candidate))
+		}
+	}
+
+	// Interleave address families, starting with IPv6
+	ips := []IP{}
+	for i := 0; i < len(v4) || i < len(v6); i++ {
+		if i < len(v6) {
+			ips = append(ips, v6[i])
+		}
+
+		if i < len(v4) {
+			ips = append(ips, v4[i])
+		}
+	}
+
+	return ips
+}
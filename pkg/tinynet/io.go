@@ -0,0 +1,31 @@
+package tinynet
+
+import (
+	"io"
+	"net"
+)
+
+type eofReader struct {
+	conn net.Conn
+}
+
+func (r eofReader) Read(b []byte) (int, error) {
+	n, err := r.conn.Read(b)
+	if err == errDisconnected {
+		return n, io.EOF
+	}
+
+	return n, err
+}
+
+func ReadFull(conn net.Conn, buf []byte) (n int, err error) {
+	return io.ReadFull(eofReader{conn}, buf)
+}
+
+func ReadAtLeast(conn net.Conn, buf []byte, min int) (n int, err error) {
+	return io.ReadAtLeast(eofReader{conn}, buf, min)
+}
+
+func NewEOFReader(conn net.Conn) io.Reader {
+	return eofReader{conn}
+}
@@ -0,0 +1,80 @@
+package tinynet
+
+import (
+	"net"
+	"sync"
+)
+
+type limitedListener struct {
+	net.Listener
+
+	sem       chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// A max of 0 or less doesn't limit the number of connections
+func NewLimitedListener(inner net.Listener, max int) net.Listener {
+	l := &limitedListener{
+		Listener: inner,
+
+		done: make(chan struct{}),
+	}
+
+	if max > 0 {
+		l.sem = make(chan struct{}, max)
+	}
+
+	return l
+}
+
+func (l *limitedListener) Accept() (net.Conn, error) {
+	if l.sem == nil {
+		return l.Listener.Accept()
+	}
+
+	// Block until a connection slot is free or the listener is closed
+	select {
+	case l.sem <- struct{}{}:
+	case <-l.done:
+		return nil, wrapError(errClosed)
+	}
+
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		<-l.sem
+
+		return nil, err
+	}
+
+	return &limitedConn{
+		Conn: conn,
+
+		release: func() {
+			<-l.sem
+		},
+	}, nil
+}
+
+func (l *limitedListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+	})
+
+	return l.Listener.Close()
+}
+
+type limitedConn struct {
+	net.Conn
+
+	release     func()
+	releaseOnce sync.Once
+}
+
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+
+	c.releaseOnce.Do(c.release)
+
+	return err
+}
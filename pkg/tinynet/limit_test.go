@@ -0,0 +1,100 @@
+package tinynet
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func acceptAll(l net.Listener) (chan net.Conn, chan error) {
+	accepted := make(chan net.Conn, 16)
+	failed := make(chan error, 1)
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				failed <- err
+
+				return
+			}
+
+			accepted <- conn
+		}
+	}()
+
+	return accepted, failed
+}
+
+func TestLimitedListener(t *testing.T) {
+	inner, addr := listenLoopback(t)
+
+	const max = 2
+	l := NewLimitedListener(inner, max)
+	defer l.Close()
+
+	accepted, failed := acceptAll(l)
+
+	// The kernel completes the handshakes even while Accept is blocked
+	for i := 0; i < max+1; i++ {
+		dialLoopback(t, addr)
+	}
+
+	conns := []net.Conn{}
+	for i := 0; i < max; i++ {
+		select {
+		case conn := <-accepted:
+			conns = append(conns, conn)
+		case <-time.After(time.Second):
+			t.Fatalf("could not accept connection %v of %v", i+1, max)
+		}
+	}
+
+	select {
+	case <-accepted:
+		t.Fatal("accepted more than", max, "connections")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if err := conns[0].Close(); err != nil {
+		t.Fatal("could not close connection:", err)
+	}
+
+	select {
+	case conn := <-accepted:
+		defer conn.Close()
+	case <-time.After(time.Second):
+		t.Fatal("could not accept connection after one was closed")
+	}
+
+	// All slots are taken again, so Accept is blocked on the semaphore
+	if err := l.Close(); err != nil {
+		t.Fatal("could not close listener:", err)
+	}
+
+	select {
+	case <-failed:
+	case <-time.After(time.Second):
+		t.Fatal("Accept didn't return after the listener was closed")
+	}
+
+	_ = conns[1].Close()
+}
+
+func TestLimitedListenerUnlimited(t *testing.T) {
+	inner, addr := listenLoopback(t)
+
+	l := NewLimitedListener(inner, 0)
+	defer l.Close()
+
+	accepted, _ := acceptAll(l)
+
+	dialLoopback(t, addr)
+
+	select {
+	case conn := <-accepted:
+		_ = conn.Close()
+	case <-time.After(time.Second):
+		t.Fatal("could not accept connection")
+	}
+}
@@ -0,0 +1,94 @@
+package tinynet
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+)
+
+// Messages are followed by alternating keys and values
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(msg string, keysAndValues ...interface{}) {}
+func (nopLogger) Info(msg string, keysAndValues ...interface{})  {}
+func (nopLogger) Error(msg string, keysAndValues ...interface{}) {}
+
+// Holds a loggerValue, as atomic.Value requires a consistent concrete type
+var defaultLogger atomic.Value
+
+type loggerValue struct {
+	Logger
+}
+
+func init() {
+	defaultLogger.Store(loggerValue{nopLogger{}})
+}
+
+// Used by listeners and connections without a logger of their own; nil disables logging
+func SetLogger(logger Logger) {
+	if logger == nil {
+		logger = nopLogger{}
+	}
+
+	defaultLogger.Store(loggerValue{logger})
+}
+
+func getLogger() Logger {
+	return defaultLogger.Load().(loggerValue).Logger
+}
+
+// Lets packages building on tinynet log to the same logger
+func DefaultLogger() Logger {
+	return getLogger()
+}
+
+type StdLogger struct {
+	logger *log.Logger
+}
+
+// A nil logger writes to the standard logger
+func NewStdLogger(logger *log.Logger) *StdLogger {
+	return &StdLogger{
+		logger: logger,
+	}
+}
+
+func (l *StdLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.output("DEBUG", msg, keysAndValues)
+}
+
+func (l *StdLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.output("INFO", msg, keysAndValues)
+}
+
+func (l *StdLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.output("ERROR", msg, keysAndValues)
+}
+
+func (l *StdLogger) output(level, msg string, keysAndValues []interface{}) {
+	line := &strings.Builder{}
+
+	fmt.Fprintf(line, "%v %v", level, msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 < len(keysAndValues) {
+			fmt.Fprintf(line, " %v=%v", keysAndValues[i], keysAndValues[i+1])
+		} else {
+			fmt.Fprintf(line, " %v=", keysAndValues[i])
+		}
+	}
+
+	if l.logger == nil {
+		_ = log.Output(3, line.String())
+
+		return
+	}
+
+	_ = l.logger.Output(3, line.String())
+}
@@ -0,0 +1,235 @@
+package tinynet
+
+import (
+	"io"
+	"net"
+	"testing"
+)
+
+func listenLoopback(tb testing.TB) (net.Listener, string) {
+	tb.Helper()
+
+	l, err := Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatal("could not listen:", err)
+	}
+	tb.Cleanup(func() {
+		_ = l.Close()
+	})
+
+	return l, l.Addr().String()
+}
+
+func startEchoServer(tb testing.TB) string {
+	tb.Helper()
+
+	l, addr := listenLoopback(tb)
+
+	go serveEcho(l)
+
+	return addr
+}
+
+func serveEcho(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer conn.Close()
+
+			_, _ = io.Copy(conn, NewEOFReader(conn))
+		}()
+	}
+}
+
+func dialLoopback(tb testing.TB, addr string) net.Conn {
+	tb.Helper()
+
+	conn, err := Dial("tcp", addr)
+	if err != nil {
+		tb.Fatal("could not dial:", err)
+	}
+	tb.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return conn
+}
+
+func BenchmarkThroughput(b *testing.B) {
+	conn := dialLoopback(b, startEchoServer(b))
+
+	msg := make([]byte, 32*1024)
+	buf := make([]byte, len(msg))
+
+	b.SetBytes(int64(len(msg)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := conn.Write(msg); err != nil {
+			b.Fatal("could not write:", err)
+		}
+
+		if _, err := ReadFull(conn, buf); err != nil {
+			b.Fatal("could not read:", err)
+		}
+	}
+}
+
+func BenchmarkConnections(b *testing.B) {
+	l, addr := listenLoopback(b)
+
+	accepted := make(chan error, 1)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				accepted <- err
+
+				return
+			}
+
+			accepted <- conn.Close()
+		}
+	}()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		conn, err := Dial("tcp", addr)
+		if err != nil {
+			b.Fatal("could not dial:", err)
+		}
+
+		if err := <-accepted; err != nil {
+			b.Fatal("could not accept:", err)
+		}
+
+		if err := conn.Close(); err != nil {
+			b.Fatal("could not close:", err)
+		}
+	}
+}
+
+func BenchmarkLatency(b *testing.B) {
+	conn := dialLoopback(b, startEchoServer(b))
+
+	msg := []byte{1}
+	buf := make([]byte, 1)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := conn.Write(msg); err != nil {
+			b.Fatal("could not write:", err)
+		}
+
+		if _, err := ReadFull(conn, buf); err != nil {
+			b.Fatal("could not read:", err)
+		}
+	}
+}
+
+// Sends each message in two writes, which Nagle's algorithm holds back until the first one is acknowledged
+func benchmarkSplitWrites(b *testing.B, noDelay bool) {
+	conn := dialLoopback(b, startEchoServer(b)).(TCPConn)
+	if err := conn.SetNoDelay(noDelay); err != nil {
+		b.Fatal("could not set no delay:", err)
+	}
+
+	header, body := []byte{1}, []byte{2}
+	buf := make([]byte, 2)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := conn.Write(header); err != nil {
+			b.Fatal("could not write:", err)
+		}
+
+		if _, err := conn.Write(body); err != nil {
+			b.Fatal("could not write:", err)
+		}
+
+		if _, err := ReadFull(conn, buf); err != nil {
+			b.Fatal("could not read:", err)
+		}
+	}
+}
+
+func BenchmarkLatencyNoDelay(b *testing.B) {
+	benchmarkSplitWrites(b, true)
+}
+
+func BenchmarkLatencyNagle(b *testing.B) {
+	benchmarkSplitWrites(b, false)
+}
+
+const (
+	scatterBuffers    = 16
+	scatterBufferSize = 1024
+)
+
+func benchmarkScatterRead(b *testing.B, read func(conn TCPConn, buffers [][]byte) error) {
+	conn := dialLoopback(b, startEchoServer(b)).(TCPConn)
+
+	msg := make([]byte, scatterBuffers*scatterBufferSize)
+	buffers := make([][]byte, scatterBuffers)
+	for i := range buffers {
+		buffers[i] = make([]byte, scatterBufferSize)
+	}
+
+	b.SetBytes(int64(len(msg)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := conn.Write(msg); err != nil {
+			b.Fatal("could not write:", err)
+		}
+
+		if err := read(conn, buffers); err != nil {
+			b.Fatal("could not read:", err)
+		}
+	}
+}
+
+func BenchmarkReadBuffers(b *testing.B) {
+	benchmarkScatterRead(b, func(conn TCPConn, buffers [][]byte) error {
+		// Reads can return early, so continue with the remaining buffers
+		remaining := append([][]byte{}, buffers...)
+		for len(remaining) > 0 {
+			n, err := conn.ReadBuffers(remaining)
+			if err != nil {
+				return err
+			}
+
+			for n > 0 {
+				if n < int64(len(remaining[0])) {
+					remaining[0] = remaining[0][n:]
+
+					break
+				}
+
+				n -= int64(len(remaining[0]))
+				remaining = remaining[1:]
+			}
+		}
+
+		return nil
+	})
+}
+
+func BenchmarkReadBuffersRead(b *testing.B) {
+	benchmarkScatterRead(b, func(conn TCPConn, buffers [][]byte) error {
+		for _, buf := range buffers {
+			if _, err := ReadFull(conn, buf); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
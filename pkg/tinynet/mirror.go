@@ -0,0 +1,95 @@
+package tinynet
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	mirrorQueueLength = 128
+)
+
+type mirrorConn struct {
+	net.Conn
+
+	shadow    net.Conn
+	failed    int32 // Accessed atomically
+	queue     chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func Mirror(primary, shadow net.Conn) net.Conn {
+	c := &mirrorConn{
+		Conn: primary,
+
+		shadow: shadow,
+		queue:  make(chan []byte, mirrorQueueLength),
+		done:   make(chan struct{}),
+	}
+
+	go c.writeLoop()
+
+	// Discard the shadow's responses so that it never blocks on a full socket buffer
+	go func() {
+		_, _ = io.Copy(ioutil.Discard, NewEOFReader(shadow))
+	}()
+
+	return c
+}
+
+func (c *mirrorConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 && atomic.LoadInt32(&c.failed) == 0 {
+		msg := make([]byte, n)
+		copy(msg, b[:n])
+
+		// The shadow is written to asynchronously so that it can't slow down the primary
+		select {
+		case c.queue <- msg:
+		case <-c.done:
+		default:
+			getLogger().Error("could not mirror written bytes: shadow is too slow", "raddr", c.shadow.RemoteAddr())
+		}
+	}
+
+	return n, err
+}
+
+func (c *mirrorConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+
+		if err := c.shadow.Close(); err != nil {
+			getLogger().Error("could not close shadow connection", "raddr", c.shadow.RemoteAddr(), "err", err)
+		}
+	})
+
+	return c.Conn.Close()
+}
+
+func (c *mirrorConn) writeLoop() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.queue:
+			for len(msg) > 0 {
+				n, err := c.shadow.Write(msg)
+				if err != nil {
+					// Shadow errors must not interrupt the connection, so stop mirroring instead
+					getLogger().Error("could not mirror written bytes", "raddr", c.shadow.RemoteAddr(), "err", err)
+
+					atomic.StoreInt32(&c.failed, 1)
+
+					return
+				}
+
+				msg = msg[n:]
+			}
+		}
+	}
+}
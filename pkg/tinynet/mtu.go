@@ -0,0 +1,10 @@
+package tinynet
+
+func (c *UDPConn) SetDontFragment(dontFragment bool) error {
+	return setDontFragment(c.fd, dontFragment, c.isIPv6())
+}
+
+// The path MTU is only known on connected sockets, e.g. after a write failed with EMSGSIZE
+func (c *UDPConn) GetMTU() (int, error) {
+	return getMTU(c.fd, c.isIPv6())
+}
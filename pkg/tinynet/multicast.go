@@ -0,0 +1,42 @@
+package tinynet
+
+import (
+	"errors"
+	"net"
+)
+
+func (c *UDPConn) isIPv6() bool {
+	if laddr, ok := c.laddr.(*UDPAddr); ok && laddr != nil {
+		return isIPv6(laddr.IP)
+	}
+
+	if raddr, ok := c.raddr.(*UDPAddr); ok && raddr != nil {
+		return isIPv6(raddr.IP)
+	}
+
+	return false
+}
+
+func (c *UDPConn) JoinMulticastGroup(ifi *net.Interface, group net.IP) error {
+	if !group.IsMulticast() {
+		return errors.New("could not join non-multicast group")
+	}
+
+	return joinGroup(c.fd, ifi, group)
+}
+
+func (c *UDPConn) LeaveMulticastGroup(ifi *net.Interface, group net.IP) error {
+	if !group.IsMulticast() {
+		return errors.New("could not leave non-multicast group")
+	}
+
+	return leaveGroup(c.fd, ifi, group)
+}
+
+func (c *UDPConn) SetMulticastTTL(ttl int) error {
+	return setMulticastTTL(c.fd, ttl, c.isIPv6())
+}
+
+func (c *UDPConn) SetMulticastLoopback(loopback bool) error {
+	return setMulticastLoopback(c.fd, loopback, c.isIPv6())
+}
@@ -0,0 +1,82 @@
+package tinynet
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestMulticastLoopback(t *testing.T) {
+	group := net.IPv4(224, 0, 0, 1)
+
+	// Reserve a port, as UDP listeners on port 0 don't report the port they were bound to
+	reserved, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := reserved.LocalAddr().(*net.UDPAddr).Port
+	_ = reserved.Close()
+
+	server, err := ListenUDP("udp4", &UDPAddr{IP: IP(net.IPv4zero), Port: port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	if err := server.JoinMulticastGroup(nil, group); err != nil {
+		t.Skip("could not join multicast group:", err)
+	}
+
+	// Replies come from the server's unicast address, so the client can't be connected to the group
+	client, err := ListenUDP("udp4", &UDPAddr{IP: IP(net.IPv4zero), Port: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if err := client.SetMulticastTTL(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.SetMulticastLoopback(true); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte("hello")
+	if _, err := client.WriteTo(expected, &UDPAddr{IP: IP(group), Port: port}); err != nil {
+		t.Skip("could not send to multicast group:", err)
+	}
+
+	if err := server.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 64)
+	n, addr, err := server.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(buf[:n], expected) {
+		t.Fatalf("server read %q, expected %q", buf[:n], expected)
+	}
+
+	// Echo the message back to the sender
+	if _, err := server.WriteTo(buf[:n], addr); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	n, _, err = client.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(buf[:n], expected) {
+		t.Fatalf("client read %q, expected %q", buf[:n], expected)
+	}
+}
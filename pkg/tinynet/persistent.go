@@ -0,0 +1,383 @@
+package tinynet
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxReconnects  = 5
+	DefaultReconnectDelay = 100 * time.Millisecond
+)
+
+var (
+	errPersistentConnClosed = errors.New("could not use closed persistent connection")
+)
+
+type PersistentConnOptions struct {
+	MaxReconnects  int // Zero uses DefaultMaxReconnects, negative disables reconnects
+	ReconnectDelay time.Duration
+
+	// Called with the error which caused the reconnect once a new connection has been established
+	OnReconnect func(err error)
+}
+
+type PersistentConn struct {
+	dial func() (net.Conn, error)
+	opts PersistentConnOptions
+
+	conn          net.Conn
+	connecting    chan struct{} // Closed once the pending dial has finished
+	readDeadline  time.Time
+	writeDeadline time.Time
+	lock          sync.Mutex
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// The first connection is dialed lazily on the first read or write
+func NewPersistentConn(dial func() (net.Conn, error), opts PersistentConnOptions) *PersistentConn {
+	if opts.MaxReconnects == 0 {
+		opts.MaxReconnects = DefaultMaxReconnects
+	}
+
+	if opts.ReconnectDelay <= 0 {
+		opts.ReconnectDelay = DefaultReconnectDelay
+	}
+
+	return &PersistentConn{
+		dial: dial,
+		opts: opts,
+		done: make(chan struct{}),
+	}
+}
+
+func (c *PersistentConn) Read(b []byte) (int, error) {
+	for {
+		conn, err := c.current()
+		if err != nil {
+			return 0, err
+		}
+
+		n, err := conn.Read(b)
+		if err == nil || n > 0 || isDeadlineExceeded(err) || c.opts.MaxReconnects < 0 {
+			return n, err
+		}
+
+		if err := c.reconnect(conn, err); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (c *PersistentConn) Write(b []byte) (int, error) {
+	written := 0
+	for {
+		conn, err := c.current()
+		if err != nil {
+			return written, err
+		}
+
+		n, err := conn.Write(b[written:])
+		written += n
+		if err == nil || isDeadlineExceeded(err) || c.opts.MaxReconnects < 0 {
+			return written, err
+		}
+
+		// Only the part which hasn't been written yet is retried
+		if err := c.reconnect(conn, err); err != nil {
+			return written, err
+		}
+	}
+}
+
+// Deadlines are set by the caller, so they must not cause a reconnect
+func isDeadlineExceeded(err error) bool {
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func (c *PersistentConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Close()
+	c.conn = nil
+
+	return err
+}
+
+func (c *PersistentConn) LocalAddr() net.Addr {
+	conn, err := c.current()
+	if err != nil {
+		return nil
+	}
+
+	return conn.LocalAddr()
+}
+
+func (c *PersistentConn) RemoteAddr() net.Addr {
+	conn, err := c.current()
+	if err != nil {
+		return nil
+	}
+
+	return conn.RemoteAddr()
+}
+
+// Deadlines are kept and re-applied to every new connection
+func (c *PersistentConn) SetDeadline(t time.Time) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.readDeadline = t
+	c.writeDeadline = t
+
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.SetDeadline(t)
+}
+
+func (c *PersistentConn) SetReadDeadline(t time.Time) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.readDeadline = t
+
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.SetReadDeadline(t)
+}
+
+func (c *PersistentConn) SetWriteDeadline(t time.Time) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.writeDeadline = t
+
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.SetWriteDeadline(t)
+}
+
+func (c *PersistentConn) isClosed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *PersistentConn) current() (net.Conn, error) {
+	for {
+		c.lock.Lock()
+		if c.isClosed() {
+			c.lock.Unlock()
+
+			return nil, wrapError(errPersistentConnClosed)
+		}
+
+		if c.conn != nil {
+			conn := c.conn
+			c.lock.Unlock()
+
+			return conn, nil
+		}
+
+		// Wait for another reader or writer which is already dialing
+		if connecting := c.connecting; connecting != nil {
+			c.lock.Unlock()
+
+			select {
+			case <-connecting:
+			case <-c.done:
+			}
+
+			continue
+		}
+
+		c.connecting = make(chan struct{})
+		c.lock.Unlock()
+
+		return c.connect(nil)
+	}
+}
+
+func (c *PersistentConn) reconnect(failed net.Conn, cause error) error {
+	c.lock.Lock()
+	if c.isClosed() {
+		c.lock.Unlock()
+
+		return wrapError(errPersistentConnClosed)
+	}
+
+	// Another reader or writer has already replaced the connection
+	if c.conn != failed {
+		c.lock.Unlock()
+
+		return nil
+	}
+
+	_ = c.conn.Close()
+	c.conn = nil
+	c.connecting = make(chan struct{})
+	c.lock.Unlock()
+
+	_, err := c.connect(cause)
+
+	return err
+}
+
+// Dials without holding the lock, so that slow dials don't block Close and other callers
+func (c *PersistentConn) connect(cause error) (net.Conn, error) {
+	attempts := c.opts.MaxReconnects
+	if attempts < 0 {
+		attempts = 1
+	}
+
+	delay := c.opts.ReconnectDelay
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		conn, err := c.dialUntilClosed()
+		if err == nil {
+			return c.install(conn, cause)
+		}
+
+		if c.isClosed() {
+			c.finishConnecting()
+
+			return nil, wrapError(errPersistentConnClosed)
+		}
+
+		lastErr = err
+
+		if attempt == attempts {
+			break
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-c.done:
+			timer.Stop()
+
+			c.finishConnecting()
+
+			return nil, wrapError(errPersistentConnClosed)
+		}
+
+		delay *= 2
+		if delay > DefaultRetryMaxDelay {
+			delay = DefaultRetryMaxDelay
+		}
+	}
+
+	c.finishConnecting()
+
+	return nil, lastErr
+}
+
+// Close returns before a pending dial does, which then closes the new connection
+func (c *PersistentConn) dialUntilClosed() (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+
+	results := make(chan result, 1)
+	go func() {
+		conn, err := c.dial()
+
+		results <- result{conn, err}
+	}()
+
+	select {
+	case r := <-results:
+		return r.conn, r.err
+	case <-c.done:
+		go func() {
+			if r := <-results; r.conn != nil {
+				_ = r.conn.Close()
+			}
+		}()
+
+		return nil, wrapError(errPersistentConnClosed)
+	}
+}
+
+func (c *PersistentConn) finishConnecting() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	close(c.connecting)
+	c.connecting = nil
+}
+
+func (c *PersistentConn) install(conn net.Conn, cause error) (net.Conn, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	close(c.connecting)
+	c.connecting = nil
+
+	if c.isClosed() {
+		_ = conn.Close()
+
+		return nil, wrapError(errPersistentConnClosed)
+	}
+
+	if err := c.applyDeadlines(conn); err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	c.conn = conn
+
+	if cause != nil && c.opts.OnReconnect != nil {
+		c.opts.OnReconnect(cause)
+	}
+
+	return conn, nil
+}
+
+func (c *PersistentConn) applyDeadlines(conn net.Conn) error {
+	if !c.readDeadline.IsZero() {
+		if err := conn.SetReadDeadline(c.readDeadline); err != nil {
+			return err
+		}
+	}
+
+	if !c.writeDeadline.IsZero() {
+		if err := conn.SetWriteDeadline(c.writeDeadline); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package tinynet
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPersistentConnDeadline(t *testing.T) {
+	addr := startEchoServer(t)
+
+	dials := int32(0)
+	conn := NewPersistentConn(func() (net.Conn, error) {
+		atomic.AddInt32(&dials, 1)
+
+		return Dial("tcp", addr)
+	}, PersistentConnOptions{})
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Fatal("read succeeded after the deadline")
+	} else if netErr, ok := err.(net.Error); !ok || !netErr.Timeout() {
+		t.Fatalf("got error %v, expected a timeout", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("read returned after %v", elapsed)
+	}
+
+	if d := atomic.LoadInt32(&dials); d != 1 {
+		t.Fatalf("dialed %v times, expected 1", d)
+	}
+}
+
+func TestPersistentConnCloseWhileDialing(t *testing.T) {
+	dialing, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+
+	conn := NewPersistentConn(func() (net.Conn, error) {
+		close(dialing)
+		<-release
+
+		return nil, errClosed
+	}, PersistentConnOptions{})
+
+	read := make(chan error, 1)
+	go func() {
+		_, err := conn.Read(make([]byte, 1))
+
+		read <- err
+	}()
+
+	<-dialing
+
+	// Neither Close nor other callers may wait for the dial
+	closed := make(chan error, 1)
+	go func() {
+		_ = conn.SetDeadline(time.Time{})
+
+		closed <- conn.Close()
+	}()
+
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close was blocked by the pending dial")
+	}
+
+	select {
+	case err := <-read:
+		if err == nil {
+			t.Fatal("read from closed persistent connection succeeded")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Read was not unblocked by Close")
+	}
+}
+
+func TestPersistentConnReconnectsDisabled(t *testing.T) {
+	l, addr := listenLoopback(t)
+
+	// Close every connection right away
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			_ = conn.Close()
+		}
+	}()
+
+	dials := int32(0)
+	conn := NewPersistentConn(func() (net.Conn, error) {
+		atomic.AddInt32(&dials, 1)
+
+		return Dial("tcp", addr)
+	}, PersistentConnOptions{MaxReconnects: -1})
+	defer conn.Close()
+
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Fatal("read from closed connection succeeded")
+	}
+
+	if d := atomic.LoadInt32(&dials); d != 1 {
+		t.Fatalf("dialed %v times, expected 1", d)
+	}
+}
@@ -0,0 +1,33 @@
+package tinynet
+
+import "net"
+
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string {
+	return "pipe"
+}
+
+func (pipeAddr) String() string {
+	return "pipe"
+}
+
+func Pipe() (net.Conn, net.Conn, error) {
+	// Create sockets
+	first, second, err := socketPair()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &TCPConn{
+		fd:    first,
+		laddr: pipeAddr{},
+		raddr: pipeAddr{},
+		state: &connState{},
+	}, &TCPConn{
+		fd:    second,
+		laddr: pipeAddr{},
+		raddr: pipeAddr{},
+		state: &connState{},
+	}, nil
+}
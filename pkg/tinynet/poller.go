@@ -0,0 +1,102 @@
+package tinynet
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultPollInterval = 100 * time.Millisecond
+)
+
+type Poller struct {
+	conns     map[int32]*TCPConn
+	callbacks map[int32]func(*TCPConn)
+	closed    bool
+	lock      sync.Mutex
+}
+
+func NewPoller() *Poller {
+	return &Poller{
+		conns:     map[int32]*TCPConn{},
+		callbacks: map[int32]func(*TCPConn){},
+	}
+}
+
+func (p *Poller) Add(conn *TCPConn, callback func(*TCPConn)) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.closed {
+		return errors.New("could not add connection to closed poller")
+	}
+
+	p.conns[conn.fd] = conn
+	p.callbacks[conn.fd] = callback
+
+	return nil
+}
+
+func (p *Poller) Remove(conn *TCPConn) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.conns, conn.fd)
+	delete(p.callbacks, conn.fd)
+}
+
+// Poll waits up to timeout for registered connections to become readable and
+// calls their callbacks; a negative timeout blocks until one is ready.
+func (p *Poller) Poll(timeout time.Duration) (int, error) {
+	p.lock.Lock()
+	fds := make([]int32, 0, len(p.conns))
+	for fd := range p.conns {
+		fds = append(fds, fd)
+	}
+	p.lock.Unlock()
+
+	ready, err := poll(fds, timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, fd := range ready {
+		p.lock.Lock()
+		conn, ok := p.conns[fd]
+		callback := p.callbacks[fd]
+		p.lock.Unlock()
+
+		if ok {
+			callback(conn)
+		}
+	}
+
+	return len(ready), nil
+}
+
+// Run polls until the poller is closed.
+func (p *Poller) Run() error {
+	for {
+		p.lock.Lock()
+		closed := p.closed
+		p.lock.Unlock()
+
+		if closed {
+			return nil
+		}
+
+		if _, err := p.Poll(DefaultPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *Poller) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.closed = true
+
+	return nil
+}
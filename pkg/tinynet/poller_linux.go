@@ -0,0 +1,82 @@
+//go:build linux && !js && !tinygo
+// +build linux,!js,!tinygo
+
+package tinynet
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const (
+	canPoll = true
+
+	pollIn  = 0x1
+	pollOut = 0x4
+	pollErr = 0x8
+	pollHup = 0x10
+)
+
+type pollFd struct {
+	fd      int32
+	events  int16
+	revents int16
+}
+
+func poll(fds []int32, timeout time.Duration) ([]int32, error) {
+	return pollEvents(fds, pollIn, timeout)
+}
+
+func waitReadable(fd int32, timeout time.Duration) (bool, error) {
+	ready, err := pollEvents([]int32{fd}, pollIn, timeout)
+
+	return len(ready) > 0, err
+}
+
+func waitWritable(fd int32, timeout time.Duration) (bool, error) {
+	ready, err := pollEvents([]int32{fd}, pollOut, timeout)
+
+	return len(ready) > 0, err
+}
+
+func pollEvents(fds []int32, events int16, timeout time.Duration) ([]int32, error) {
+	if len(fds) == 0 {
+		time.Sleep(timeout)
+
+		return nil, nil
+	}
+
+	pollFds := make([]pollFd, len(fds))
+	for i, fd := range fds {
+		pollFds[i] = pollFd{
+			fd:     fd,
+			events: events,
+		}
+	}
+
+	// A nil timespec blocks until an fd is ready
+	var ts *syscall.Timespec
+	if timeout >= 0 {
+		t := syscall.NsecToTimespec(timeout.Nanoseconds())
+		ts = &t
+	}
+
+	n, _, errno := syscall.Syscall6(syscall.SYS_PPOLL, uintptr(unsafe.Pointer(&pollFds[0])), uintptr(len(pollFds)), uintptr(unsafe.Pointer(ts)), 0, 0, 0)
+	if errno != 0 {
+		if errno == syscall.EINTR {
+			return nil, nil
+		}
+
+		return nil, errno
+	}
+
+	ready := make([]int32, 0, n)
+	for _, pollFd := range pollFds {
+		if pollFd.revents&(events|pollErr|pollHup) != 0 {
+			ready = append(ready, pollFd.fd)
+		}
+	}
+
+	return ready, nil
+}
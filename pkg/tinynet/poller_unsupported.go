@@ -0,0 +1,22 @@
+//go:build !linux || js || tinygo
+// +build !linux js tinygo
+
+package tinynet
+
+import "time"
+
+const (
+	canPoll = false
+)
+
+func poll(fds []int32, timeout time.Duration) ([]int32, error) {
+	return nil, errUnsupported
+}
+
+func waitReadable(fd int32, timeout time.Duration) (bool, error) {
+	return false, errUnsupported
+}
+
+func waitWritable(fd int32, timeout time.Duration) (bool, error) {
+	return false, errUnsupported
+}
@@ -0,0 +1,161 @@
+package tinynet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxIdle = 2
+)
+
+type Pool struct {
+	New         func() (net.Conn, error)
+	MaxIdle     int
+	MaxOpen     int
+	IdleTimeout time.Duration
+
+	idle     []idleConn
+	open     int
+	closed   bool
+	released chan struct{}
+	lock     sync.Mutex
+}
+
+type idleConn struct {
+	conn  net.Conn
+	since time.Time
+}
+
+func (p *Pool) Get(ctx context.Context) (net.Conn, error) {
+	for {
+		p.lock.Lock()
+
+		if p.closed {
+			p.lock.Unlock()
+
+			return nil, errors.New("could not get connection from closed pool")
+		}
+
+		// Reuse the most recently returned idle connection
+		for len(p.idle) > 0 {
+			candidate := p.idle[len(p.idle)-1]
+			p.idle = p.idle[:len(p.idle)-1]
+
+			if p.IdleTimeout > 0 && time.Since(candidate.since) > p.IdleTimeout {
+				_ = candidate.conn.Close()
+				p.open--
+
+				continue
+			}
+
+			p.lock.Unlock()
+
+			return candidate.conn, nil
+		}
+
+		// Open a new connection
+		if p.MaxOpen <= 0 || p.open < p.MaxOpen {
+			p.open++
+			p.lock.Unlock()
+
+			conn, err := p.New()
+			if err != nil {
+				p.lock.Lock()
+				p.open--
+				p.release()
+				p.lock.Unlock()
+
+				return nil, err
+			}
+
+			return conn, nil
+		}
+
+		// Wait for a connection to be returned
+		released := p.released
+		if released == nil {
+			released = make(chan struct{})
+			p.released = released
+		}
+
+		p.lock.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+func (p *Pool) Put(conn net.Conn) error {
+	alive := isAlive(conn)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	defer p.release()
+
+	maxIdle := p.MaxIdle
+	if maxIdle == 0 {
+		maxIdle = DefaultMaxIdle
+	}
+
+	if p.closed || !alive || len(p.idle) >= maxIdle {
+		p.open--
+
+		return conn.Close()
+	}
+
+	p.idle = append(p.idle, idleConn{
+		conn:  conn,
+		since: time.Now(),
+	})
+
+	return nil
+}
+
+func (p *Pool) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	defer p.release()
+
+	p.closed = true
+
+	var err error
+	for _, candidate := range p.idle {
+		if e := candidate.conn.Close(); e != nil {
+			err = e
+		}
+
+		p.open--
+	}
+
+	p.idle = nil
+
+	return err
+}
+
+func (p *Pool) release() {
+	if p.released != nil {
+		close(p.released)
+
+		p.released = nil
+	}
+}
+
+func isAlive(conn net.Conn) bool {
+	switch c := conn.(type) {
+	case TCPConn:
+		return isConnAlive(c.fd)
+	case *TCPConn:
+		return isConnAlive(c.fd)
+	default:
+		return true
+	}
+}
@@ -0,0 +1,24 @@
+package tinynet
+
+type QuickAckConn struct {
+	TCPConn
+}
+
+func NewQuickAckConn(inner *TCPConn) (*QuickAckConn, error) {
+	if err := inner.SetQuickAck(true); err != nil {
+		return nil, err
+	}
+
+	return &QuickAckConn{*inner}, nil
+}
+
+func (c *QuickAckConn) Read(b []byte) (int, error) {
+	n, err := c.TCPConn.Read(b)
+
+	// Re-enable after every read since the kernel resets it
+	if n > 0 {
+		_ = c.SetQuickAck(true)
+	}
+
+	return n, err
+}
@@ -0,0 +1,154 @@
+package tinynet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+type AddrPreference int
+
+const (
+	PreferAny AddrPreference = iota
+	PreferIPv4
+	PreferIPv6
+)
+
+var (
+	DefaultResolver = &Resolver{}
+)
+
+type Resolver struct {
+	Prefer AddrPreference
+}
+
+func (r *Resolver) ResolveTCPAddr(ctx context.Context, network, address string) (*TCPAddr, error) {
+	ip, port, zone, err := r.resolveAddr(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TCPAddr{
+		stringAddr: address,
+
+		IP:   ip,
+		Port: port,
+		Zone: zone,
+	}, nil
+}
+
+func (r *Resolver) ResolveUDPAddr(ctx context.Context, network, address string) (*UDPAddr, error) {
+	ip, port, zone, err := r.resolveAddr(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UDPAddr{
+		stringAddr: address,
+
+		IP:   ip,
+		Port: port,
+		Zone: zone,
+	}, nil
+}
+
+func (r *Resolver) lookupIPs(ctx context.Context, host string) ([]net.IP, error) {
+	hosts, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	ips := []net.IP{}
+	for _, candidate := range hosts {
+		if ip := net.ParseIP(candidate); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+
+	if len(ips) == 0 {
+		return nil, errors.New("could not resolve host")
+	}
+
+	return ips, nil
+}
+
+func (r *Resolver) LookupIP(ctx context.Context, host string) (IP, error) {
+	ips, err := r.lookupIPs(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ip := range ips {
+		ip4 := ip.To4()
+
+		if r.Prefer == PreferIPv4 && ip4 != nil {
+			return IP(ip4), nil
+		}
+
+		if r.Prefer == PreferIPv6 && ip4 == nil {
+			return IP(ip), nil
+		}
+	}
+
+	// Fall back to the first result
+	if ip4 := ips[0].To4(); ip4 != nil {
+		return IP(ip4), nil
+	}
+
+	return IP(ips[0]), nil
+}
+
+func (r *Resolver) resolveAddr(ctx context.Context, network, address string) (IP, int, string, error) {
+	host, port, err := splitAddr(network, address)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	if ip, zone, err := parseIP(host); err == nil {
+		ip, err = ipForNetwork(network, ip)
+		if err != nil {
+			return nil, 0, "", err
+		}
+
+		return ip, port, zone, nil
+	}
+
+	ip, err := r.lookupIPForNetwork(ctx, network, host)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	return ip, port, "", nil
+}
+
+func (r *Resolver) lookupIPForNetwork(ctx context.Context, network, host string) (IP, error) {
+	v4, v6 := false, false
+	switch network {
+	case "tcp4", "udp4":
+		v4 = true
+	case "tcp6", "udp6":
+		v6 = true
+	default:
+		return r.LookupIP(ctx, host)
+	}
+
+	ips, err := r.lookupIPs(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ip := range ips {
+		ip4 := ip.To4()
+
+		if v4 && ip4 != nil {
+			return IP(ip4), nil
+		}
+
+		if v6 && ip4 == nil {
+			return IP(ip), nil
+		}
+	}
+
+	return nil, fmt.Errorf("could not find address for %v with network %v", host, network)
+}
@@ -0,0 +1,153 @@
+package tinynet
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"syscall"
+	"time"
+)
+
+const (
+	DefaultRetryAttempts     = 3
+	DefaultRetryInitialDelay = 100 * time.Millisecond
+	DefaultRetryMaxDelay     = 5 * time.Second
+	DefaultRetryMultiplier   = 2
+)
+
+type ContextDialer interface {
+	Dial(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// Adapts DialContext methods, e.g. Dialer's, to ContextDialer
+type ContextDialerFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+func (f ContextDialerFunc) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	return f(ctx, network, address)
+}
+
+type RetryOptions struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	Jitter       bool
+}
+
+type RetryDialer struct {
+	base ContextDialer
+	opts RetryOptions
+}
+
+func NewRetryDialer(base ContextDialer, opts RetryOptions) *RetryDialer {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultRetryAttempts
+	}
+
+	if opts.InitialDelay <= 0 {
+		opts.InitialDelay = DefaultRetryInitialDelay
+	}
+
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = DefaultRetryMaxDelay
+	}
+
+	if opts.Multiplier < 1 {
+		opts.Multiplier = DefaultRetryMultiplier
+	}
+
+	return &RetryDialer{
+		base: base,
+		opts: opts,
+	}
+}
+
+func isRetryable(err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	if temporary, ok := err.(interface{ Temporary() bool }); ok && temporary.Temporary() {
+		return true
+	}
+
+	return false
+}
+
+func (d *RetryDialer) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	delay := d.opts.InitialDelay
+
+	var lastErr error
+	for attempt := 1; attempt <= d.opts.MaxAttempts; attempt++ {
+		conn, err := d.base.Dial(ctx, network, address)
+		if err == nil {
+			return conn, nil
+		}
+
+		lastErr = err
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if !isRetryable(err) || attempt == d.opts.MaxAttempts {
+			break
+		}
+
+		wait := delay
+		if d.opts.Jitter {
+			// Wait somewhere between half and the full delay
+			wait = wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+		}
+
+		getLogger().Error("could not dial, retrying", "addr", address, "attempt", attempt, "maxAttempts", d.opts.MaxAttempts, "wait", wait, "err", err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, ctx.Err()
+		}
+
+		delay = time.Duration(float64(delay) * d.opts.Multiplier)
+		if delay > d.opts.MaxDelay {
+			delay = d.opts.MaxDelay
+		}
+	}
+
+	return nil, lastErr
+}
+
+func WaitAndDial(ctx context.Context, network, address string, pollInterval time.Duration) (net.Conn, error) {
+	if pollInterval <= 0 {
+		pollInterval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		conn, err := DialContext(ctx, network, address)
+		if err == nil {
+			return conn, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		// Nothing is listening yet
+		if !errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package tinynet
+
+import (
+	"bufio"
+	"net"
+)
+
+type Scanner struct {
+	// Defaults to bufio.ScanLines; changes after the first call to Scan are ignored
+	SplitFunc bufio.SplitFunc
+	// Defaults to bufio.MaxScanTokenSize; changes after the first call to Scan are ignored
+	MaxTokenSize int
+
+	conn    net.Conn
+	scanner *bufio.Scanner
+}
+
+func NewScanner(conn net.Conn) *Scanner {
+	return &Scanner{
+		conn: conn,
+	}
+}
+
+// Disconnects end the scan like io.EOF, so Err returns nil for them
+func (s *Scanner) Scan() bool {
+	if s.scanner == nil {
+		s.scanner = bufio.NewScanner(eofReader{s.conn})
+
+		if s.SplitFunc != nil {
+			s.scanner.Split(s.SplitFunc)
+		}
+
+		if s.MaxTokenSize > 0 {
+			s.scanner.Buffer(nil, s.MaxTokenSize)
+		}
+	}
+
+	return s.scanner.Scan()
+}
+
+func (s *Scanner) Bytes() []byte {
+	if s.scanner == nil {
+		return nil
+	}
+
+	return s.scanner.Bytes()
+}
+
+func (s *Scanner) Text() string {
+	if s.scanner == nil {
+		return ""
+	}
+
+	return s.scanner.Text()
+}
+
+func (s *Scanner) Err() error {
+	if s.scanner == nil {
+		return nil
+	}
+
+	return s.scanner.Err()
+}
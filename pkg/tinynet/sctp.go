@@ -0,0 +1,283 @@
+package tinynet
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/alphahorizonio/unisockets/pkg/unisockets"
+)
+
+const (
+	sctpMaxMessageSize = 1 << 16
+)
+
+type SCTPAddr struct {
+	stringAddr string
+
+	IP   IP
+	Port int
+	Zone string
+}
+
+func (s *SCTPAddr) Network() string {
+	return "sctp"
+}
+
+func (s *SCTPAddr) String() string {
+	if s.stringAddr == "" {
+		return formatAddr(s.IP, s.Port, s.Zone)
+	}
+
+	return s.stringAddr
+}
+
+func ResolveSCTPAddr(network, address string) (*SCTPAddr, error) {
+	ip, port, zone, err := DefaultResolver.resolveAddr(context.Background(), network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SCTPAddr{
+		stringAddr: address,
+
+		IP:   ip,
+		Port: port,
+		Zone: zone,
+	}, nil
+}
+
+func ListenSCTP(laddr *SCTPAddr) (*SCTPListener, error) {
+	// Create socket
+	serverSocket, err := socketSCTP(isIPv6(laddr.IP))
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't leak the socket if any of the following steps fail
+	listening := false
+	defer func() {
+		if !listening {
+			_ = closeSocket(serverSocket)
+		}
+	}()
+
+	// Set socket options
+	if err := setReuseAddr(serverSocket, true); err != nil {
+		return nil, err
+	}
+
+	if err := subscribeSCTPDataEvents(serverSocket); err != nil {
+		return nil, err
+	}
+
+	// Bind
+	if err := bindSocket(serverSocket, laddr.IP, laddr.Port, laddr.Zone); err != nil {
+		return nil, err
+	}
+
+	// Listen
+	if err := unisockets.Listen(serverSocket, DefaultBacklog); err != nil {
+		return nil, err
+	}
+
+	listening = true
+
+	return &SCTPListener{
+		fd:   serverSocket,
+		addr: laddr,
+	}, nil
+}
+
+// Uses one-to-one style SOCK_STREAM sockets instead of SOCK_SEQPACKET, as
+// one-to-many sockets can't accept individual associations as net.Conns.
+type SCTPListener struct {
+	fd     int32
+	addr   *SCTPAddr
+	closed int32
+}
+
+func (l *SCTPListener) Close() error {
+	// Subsequent closes are no-ops
+	if !atomic.CompareAndSwapInt32(&l.closed, 0, 1) {
+		return nil
+	}
+
+	return shutdownAndClose(l.fd)
+}
+
+func (l *SCTPListener) Addr() net.Addr {
+	return l.addr
+}
+
+func (l *SCTPListener) Accept() (net.Conn, error) {
+	return l.AcceptSCTP()
+}
+
+func (l *SCTPListener) AcceptSCTP() (*SCTPConn, error) {
+	// The fd might already belong to another socket
+	if atomic.LoadInt32(&l.closed) == 1 {
+		return nil, wrapError(errClosed)
+	}
+
+	var clientSocket int32
+	var ip IP
+	var port int
+	var err error
+
+	// Accept
+	if isIPv6(l.addr.IP) {
+		clientSocket, ip, port, err = accept6(l.fd)
+	} else {
+		clientAddress := unisockets.SockaddrIn{}
+
+		clientSocket, err = unisockets.Accept(l.fd, &clientAddress)
+		ip, port = ipFromSockaddrIn(clientAddress), portFromSockaddrIn(clientAddress)
+	}
+	if err != nil {
+		if atomic.LoadInt32(&l.closed) == 1 {
+			return nil, wrapError(errClosed)
+		}
+
+		if isTimeout(err) {
+			return nil, os.ErrDeadlineExceeded
+		}
+
+		return nil, err
+	}
+
+	return &SCTPConn{
+		fd:    clientSocket,
+		laddr: l.addr,
+		raddr: &SCTPAddr{
+			IP:   ip,
+			Port: port,
+		},
+	}, nil
+}
+
+func DialSCTP(laddr, raddr *SCTPAddr) (*SCTPConn, error) {
+	// Create socket
+	clientSocket, err := socketSCTP(isIPv6(raddr.IP))
+	if err != nil {
+		return nil, err
+	}
+
+	// Set socket options
+	if err := subscribeSCTPDataEvents(clientSocket); err != nil {
+		_ = closeSocket(clientSocket)
+
+		return nil, err
+	}
+
+	// Bind
+	if laddr != nil {
+		if err := bindSocket(clientSocket, laddr.IP, laddr.Port, laddr.Zone); err != nil {
+			_ = closeSocket(clientSocket)
+
+			return nil, err
+		}
+	}
+
+	// Connect
+	if err := connectSocket(clientSocket, raddr.IP, raddr.Port, raddr.Zone); err != nil {
+		_ = closeSocket(clientSocket)
+
+		return nil, err
+	}
+
+	conn := &SCTPConn{
+		fd:    clientSocket,
+		raddr: raddr,
+	}
+
+	if laddr != nil {
+		conn.laddr = laddr
+	}
+
+	return conn, nil
+}
+
+type SCTPConn struct {
+	fd     int32
+	closed int32
+
+	laddr net.Addr
+	raddr net.Addr
+}
+
+// Read and Write use stream 0
+func (c *SCTPConn) Read(b []byte) (int, error) {
+	return readSocket(c.fd, b, 0)
+}
+
+func (c *SCTPConn) Write(b []byte) (int, error) {
+	return writeSocket(c.fd, b, 0)
+}
+
+func (c *SCTPConn) SendMsg(streamID uint16, b []byte) (int, error) {
+	n, err := sendSCTPMsg(c.fd, streamID, b)
+	if err != nil {
+		if isTimeout(err) {
+			return 0, wrapError(os.ErrDeadlineExceeded)
+		}
+
+		return 0, wrapError(err)
+	}
+
+	return n, nil
+}
+
+func (c *SCTPConn) RecvMsg() (uint16, []byte, error) {
+	b := make([]byte, sctpMaxMessageSize)
+
+	n, streamID, err := recvSCTPMsg(c.fd, b)
+	if err != nil {
+		if isTimeout(err) {
+			return 0, nil, wrapError(os.ErrDeadlineExceeded)
+		}
+
+		return 0, nil, wrapError(err)
+	}
+
+	if n == 0 {
+		return 0, nil, errDisconnected
+	}
+
+	return streamID, b[:n], nil
+}
+
+func (c *SCTPConn) Close() error {
+	// Subsequent closes are no-ops
+	if !atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		return nil
+	}
+
+	return shutdownAndClose(c.fd)
+}
+
+func (c *SCTPConn) LocalAddr() net.Addr {
+	return c.laddr
+}
+
+func (c *SCTPConn) RemoteAddr() net.Addr {
+	return c.raddr
+}
+
+func (c *SCTPConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+
+	return c.SetWriteDeadline(t)
+}
+
+func (c *SCTPConn) SetReadDeadline(t time.Time) error {
+	return setReadTimeout(c.fd, t)
+}
+
+func (c *SCTPConn) SetWriteDeadline(t time.Time) error {
+	return setWriteTimeout(c.fd, t)
+}
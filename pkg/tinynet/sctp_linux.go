@@ -0,0 +1,86 @@
+//go:build linux && !js && !tinygo
+// +build linux,!js,!tinygo
+
+package tinynet
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const (
+	ipprotoSCTP = 0x84
+	sctpSndRcv  = 0x1
+	sctpEvents  = 0xb
+)
+
+// struct sctp_sndrcvinfo
+type sctpSndRcvInfo struct {
+	Stream     uint16
+	SSN        uint16
+	Flags      uint16
+	_          uint16
+	PPID       uint32
+	Context    uint32
+	TimeToLive uint32
+	TSN        uint32
+	CumTSN     uint32
+	AssocID    int32
+}
+
+const sizeofSCTPSndRcvInfo = int(unsafe.Sizeof(sctpSndRcvInfo{}))
+
+func socketSCTP(v6 bool) (int32, error) {
+	family := syscall.AF_INET
+	if v6 {
+		family = syscall.AF_INET6
+	}
+
+	// One-to-one style, which behaves like a TCP socket
+	fd, err := syscall.Socket(family, syscall.SOCK_STREAM, ipprotoSCTP)
+
+	return int32(fd), err
+}
+
+func subscribeSCTPDataEvents(fd int32) error {
+	// The first field of struct sctp_event_subscribe enables the sctp_sndrcvinfo control message
+	return syscall.SetsockoptString(int(fd), ipprotoSCTP, sctpEvents, "\x01")
+}
+
+func sendSCTPMsg(fd int32, streamID uint16, b []byte) (int, error) {
+	oob := make([]byte, syscall.CmsgSpace(sizeofSCTPSndRcvInfo))
+
+	h := (*syscall.Cmsghdr)(unsafe.Pointer(&oob[0]))
+	h.Level = ipprotoSCTP
+	h.Type = sctpSndRcv
+	h.SetLen(syscall.CmsgLen(sizeofSCTPSndRcvInfo))
+
+	info := (*sctpSndRcvInfo)(unsafe.Pointer(&oob[syscall.CmsgLen(0)]))
+	info.Stream = streamID
+
+	return syscall.SendmsgN(int(fd), b, oob, nil, 0)
+}
+
+func recvSCTPMsg(fd int32, b []byte) (int, uint16, error) {
+	oob := make([]byte, syscall.CmsgSpace(sizeofSCTPSndRcvInfo))
+
+	n, oobn, _, _, err := syscall.Recvmsg(int(fd), b, oob, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, msg := range msgs {
+		if msg.Header.Level == ipprotoSCTP && msg.Header.Type == sctpSndRcv && len(msg.Data) >= sizeofSCTPSndRcvInfo {
+			info := (*sctpSndRcvInfo)(unsafe.Pointer(&msg.Data[0]))
+
+			return n, info.Stream, nil
+		}
+	}
+
+	return n, 0, nil
+}
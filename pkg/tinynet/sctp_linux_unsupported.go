@@ -0,0 +1,20 @@
+//go:build !linux || js || tinygo
+// +build !linux js tinygo
+
+package tinynet
+
+func socketSCTP(v6 bool) (int32, error) {
+	return -1, errUnsupported
+}
+
+func subscribeSCTPDataEvents(fd int32) error {
+	return errUnsupported
+}
+
+func sendSCTPMsg(fd int32, streamID uint16, b []byte) (int, error) {
+	return 0, errUnsupported
+}
+
+func recvSCTPMsg(fd int32, b []byte) (int, uint16, error) {
+	return 0, 0, errUnsupported
+}
@@ -0,0 +1,44 @@
+//go:build linux && !js && !tinygo
+// +build linux,!js,!tinygo
+
+package tinynet
+
+import (
+	"os"
+	"syscall"
+)
+
+const maxSendfileSize = 1 << 30
+
+func sendFile(fd int32, f *os.File) (int64, bool, error) {
+	written := int64(0)
+	for {
+		// A nil offset makes sendfile continue from and advance the file's offset
+		n, err := syscall.Sendfile(int(fd), int(f.Fd()), nil, maxSendfileSize)
+		if n > 0 {
+			written += int64(n)
+		}
+
+		if err == syscall.EINTR {
+			continue
+		}
+
+		if err != nil {
+			// Fall back to copying if the file type doesn't support sendfile
+			if written == 0 && (err == syscall.EINVAL || err == syscall.ENOSYS) {
+				return 0, false, nil
+			}
+
+			if isTimeout(err) {
+				return written, true, os.ErrDeadlineExceeded
+			}
+
+			return written, true, err
+		}
+
+		// EOF
+		if n == 0 {
+			return written, true, nil
+		}
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !linux || js || tinygo
+// +build !linux js tinygo
+
+package tinynet
+
+import "os"
+
+func sendFile(fd int32, f *os.File) (int64, bool, error) {
+	// Files are always copied through user space on this platform
+
+	return 0, false, nil
+}
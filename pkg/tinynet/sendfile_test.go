@@ -0,0 +1,56 @@
+package tinynet
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+const sendfilePayloadSize = 1024 * 1024
+
+func benchmarkSendFile(b *testing.B, send func(conn TCPConn, f *os.File) (int64, error)) {
+	f, err := ioutil.TempFile("", "tinynet-sendfile")
+	if err != nil {
+		b.Fatal("could not create file:", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if _, err := f.Write(make([]byte, sendfilePayloadSize)); err != nil {
+		b.Fatal("could not write file:", err)
+	}
+
+	conn := dialLoopback(b, startDiscardServer(b)).(TCPConn)
+
+	b.SetBytes(sendfilePayloadSize)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			b.Fatal("could not seek:", err)
+		}
+
+		n, err := send(conn, f)
+		if err != nil {
+			b.Fatal("could not send file:", err)
+		}
+
+		if n != sendfilePayloadSize {
+			b.Fatalf("sent %v bytes, expected %v", n, sendfilePayloadSize)
+		}
+	}
+}
+
+func BenchmarkSendFile(b *testing.B) {
+	benchmarkSendFile(b, func(conn TCPConn, f *os.File) (int64, error) {
+		return conn.ReadFrom(f)
+	})
+}
+
+func BenchmarkSendFileCopy(b *testing.B) {
+	benchmarkSendFile(b, func(conn TCPConn, f *os.File) (int64, error) {
+		// Hide the file's type so that the data is copied through user space
+		return conn.ReadFrom(struct{ io.Reader }{f})
+	})
+}
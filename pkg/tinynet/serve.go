@@ -0,0 +1,117 @@
+package tinynet
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+
+	tinytls "github.com/alphahorizonio/tinynet/pkg/tls"
+)
+
+const (
+	minAcceptDelay = time.Millisecond
+	maxAcceptDelay = time.Second
+)
+
+func (l *TCPListener) Serve(handler func(net.Conn)) error {
+	delay := time.Duration(0)
+
+	for {
+		conn, err := l.AcceptTCP()
+		if err != nil {
+			if l.isClosed() {
+				return nil
+			}
+
+			// Back off on temporary errors like running out of file descriptors
+			if temporary, ok := err.(interface{ Temporary() bool }); ok && temporary.Temporary() {
+				if delay == 0 {
+					delay = minAcceptDelay
+				} else {
+					delay *= 2
+				}
+
+				if delay > maxAcceptDelay {
+					delay = maxAcceptDelay
+				}
+
+				time.Sleep(delay)
+
+				continue
+			}
+
+			return err
+		}
+
+		delay = 0
+
+		go handler(conn)
+	}
+}
+
+func (l *TCPListener) ServeContext(ctx context.Context, handler func(net.Conn)) error {
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = l.Close()
+		case <-done:
+		}
+	}()
+
+	if err := l.Serve(handler); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+func listenTCPAddress(address string) (*TCPListener, error) {
+	laddr, err := ResolveTCPAddr("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return ListenTCP("tcp", laddr)
+}
+
+func ListenAndServe(address string, handler func(net.Conn)) error {
+	l, err := listenTCPAddress(address)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	return l.Serve(handler)
+}
+
+func ListenAndServeTLS(address, certFile, keyFile string, handler func(net.Conn)) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+
+	l, err := listenTCPAddress(address)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	return l.Serve(func(conn net.Conn) {
+		tlsConn, err := tinytls.UpgradeTLS(conn, cfg, tinytls.RoleServer)
+		if err != nil {
+			_ = conn.Close()
+
+			return
+		}
+
+		handler(tlsConn)
+	})
+}
@@ -0,0 +1,328 @@
+//go:build !js && !tinygo
+// +build !js,!tinygo
+
+package tinynet
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	sockDgram = int32(syscall.SOCK_DGRAM)
+	shutRd    = int32(syscall.SHUT_RD)
+	shutWr    = int32(syscall.SHUT_WR)
+
+	msgOOB      = int32(syscall.MSG_OOB)
+	msgDontWait = int32(syscall.MSG_DONTWAIT)
+	msgPeek     = int32(syscall.MSG_PEEK)
+	msgWaitAll  = int32(syscall.MSG_WAITALL)
+
+	maxIovecs = 1024 // IOV_MAX
+)
+
+func recvFrom(fd int32, b []byte) (int, IP, int, error) {
+	n, from, err := syscall.Recvfrom(int(fd), b, 0)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+
+	switch addr := from.(type) {
+	case *syscall.SockaddrInet4:
+		return n, IP(addr.Addr[:]), addr.Port, nil
+	case *syscall.SockaddrInet6:
+		return n, IP(addr.Addr[:]), addr.Port, nil
+	default:
+		return n, nil, 0, nil
+	}
+}
+
+func sendTo(fd int32, b []byte, ip IP, port int, zone string) error {
+	if isIPv6(ip) {
+		addr, err := toSockaddrInet6(ip, port, zone)
+		if err != nil {
+			return err
+		}
+
+		return syscall.Sendto(int(fd), b, 0, addr)
+	}
+
+	addr := &syscall.SockaddrInet4{
+		Port: port,
+	}
+	copy(addr.Addr[:], net.IP(ip).To4())
+
+	return syscall.Sendto(int(fd), b, 0, addr)
+}
+
+func closeSocket(fd int32) error {
+	return syscall.Close(int(fd))
+}
+
+func socket6(sotype int32) (int32, error) {
+	fd, err := syscall.Socket(syscall.AF_INET6, int(sotype), 0)
+
+	return int32(fd), err
+}
+
+func toSockaddrInet6(ip IP, port int, zone string) (*syscall.SockaddrInet6, error) {
+	addr := &syscall.SockaddrInet6{
+		Port: port,
+	}
+	copy(addr.Addr[:], ip)
+
+	if zone != "" {
+		if id, err := strconv.Atoi(zone); err == nil {
+			addr.ZoneId = uint32(id)
+		} else {
+			ifi, err := net.InterfaceByName(zone)
+			if err != nil {
+				return nil, err
+			}
+
+			addr.ZoneId = uint32(ifi.Index)
+		}
+	}
+
+	return addr, nil
+}
+
+func bind6(fd int32, ip IP, port int, zone string) error {
+	addr, err := toSockaddrInet6(ip, port, zone)
+	if err != nil {
+		return err
+	}
+
+	return syscall.Bind(int(fd), addr)
+}
+
+func connect6(fd int32, ip IP, port int, zone string) error {
+	addr, err := toSockaddrInet6(ip, port, zone)
+	if err != nil {
+		return err
+	}
+
+	return syscall.Connect(int(fd), addr)
+}
+
+func accept6(fd int32) (int32, IP, int, error) {
+	nfd, from, err := syscall.Accept(int(fd))
+	if err != nil {
+		return -1, nil, 0, err
+	}
+
+	if addr, ok := from.(*syscall.SockaddrInet6); ok {
+		return int32(nfd), IP(addr.Addr[:]), addr.Port, nil
+	}
+
+	return int32(nfd), nil, 0, nil
+}
+
+func isConnAlive(fd int32) bool {
+	// Peek without blocking; an orderly shutdown by the peer reads as zero bytes
+	n, _, err := syscall.Recvfrom(int(fd), make([]byte, 1), syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+	if err != nil {
+		return err == syscall.EAGAIN || err == syscall.EWOULDBLOCK
+	}
+
+	return n > 0
+}
+
+func socketPair() (int32, int32, error) {
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	return int32(fds[0]), int32(fds[1]), nil
+}
+
+func dupSocket(fd int32) (int32, error) {
+	nfd, err := syscall.Dup(int(fd))
+
+	return int32(nfd), err
+}
+
+func isInProgress(err error) bool {
+	errno, ok := err.(syscall.Errno)
+
+	return ok && errno == syscall.EINPROGRESS
+}
+
+func socketError(fd int32) error {
+	errno, err := syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_ERROR)
+	if err != nil {
+		return err
+	}
+
+	if errno != 0 {
+		return syscall.Errno(errno)
+	}
+
+	return nil
+}
+
+func socketUnix(sotype int32) (int32, error) {
+	fd, err := syscall.Socket(syscall.AF_UNIX, int(sotype), 0)
+
+	return int32(fd), err
+}
+
+func bindUnix(fd int32, name string) error {
+	return syscall.Bind(int(fd), &syscall.SockaddrUnix{Name: name})
+}
+
+func connectUnix(fd int32, name string) error {
+	return syscall.Connect(int(fd), &syscall.SockaddrUnix{Name: name})
+}
+
+func acceptUnix(fd int32) (int32, string, error) {
+	nfd, from, err := syscall.Accept(int(fd))
+	if err != nil {
+		return -1, "", err
+	}
+
+	if addr, ok := from.(*syscall.SockaddrUnix); ok {
+		return int32(nfd), addr.Name, nil
+	}
+
+	return int32(nfd), "", nil
+}
+
+func recvFromUnix(fd int32, b []byte) (int, string, error) {
+	n, from, err := syscall.Recvfrom(int(fd), b, 0)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if addr, ok := from.(*syscall.SockaddrUnix); ok {
+		return n, addr.Name, nil
+	}
+
+	return n, "", nil
+}
+
+func sendToUnix(fd int32, b []byte, name string) error {
+	return syscall.Sendto(int(fd), b, 0, &syscall.SockaddrUnix{Name: name})
+}
+
+func toIovecs(buffers [][]byte) []syscall.Iovec {
+	iovecs := make([]syscall.Iovec, 0, len(buffers))
+	for _, b := range buffers {
+		if len(iovecs) == maxIovecs {
+			break
+		}
+
+		if len(b) == 0 {
+			continue
+		}
+
+		iovec := syscall.Iovec{Base: &b[0]}
+		iovec.SetLen(len(b))
+
+		iovecs = append(iovecs, iovec)
+	}
+
+	return iovecs
+}
+
+func writev(fd int32, buffers [][]byte) (int64, error) {
+	iovecs := toIovecs(buffers)
+	if len(iovecs) == 0 {
+		return 0, nil
+	}
+
+	for {
+		n, _, errno := syscall.Syscall(syscall.SYS_WRITEV, uintptr(fd), uintptr(unsafe.Pointer(&iovecs[0])), uintptr(len(iovecs)))
+		if errno == syscall.EINTR {
+			continue
+		}
+
+		if errno != 0 {
+			return 0, errno
+		}
+
+		return int64(n), nil
+	}
+}
+
+func readv(fd int32, buffers [][]byte) (int64, error) {
+	iovecs := toIovecs(buffers)
+	if len(iovecs) == 0 {
+		return 0, nil
+	}
+
+	for {
+		n, _, errno := syscall.Syscall(syscall.SYS_READV, uintptr(fd), uintptr(unsafe.Pointer(&iovecs[0])), uintptr(len(iovecs)))
+		if errno == syscall.EINTR {
+			continue
+		}
+
+		if errno != 0 {
+			return 0, errno
+		}
+
+		return int64(n), nil
+	}
+}
+
+func recvMsg(fd int32, b []byte, oob []byte, flags int) (int, int, int, IP, int, error) {
+	n, oobn, recvflags, from, err := syscall.Recvmsg(int(fd), b, oob, flags)
+	if err != nil {
+		return 0, 0, 0, nil, 0, err
+	}
+
+	switch addr := from.(type) {
+	case *syscall.SockaddrInet4:
+		return n, oobn, recvflags, IP(addr.Addr[:]), addr.Port, nil
+	case *syscall.SockaddrInet6:
+		return n, oobn, recvflags, IP(addr.Addr[:]), addr.Port, nil
+	default:
+		return n, oobn, recvflags, nil, 0, nil
+	}
+}
+
+func sendMsg(fd int32, b []byte, oob []byte, flags int) (int, error) {
+	return syscall.SendmsgN(int(fd), b, oob, nil, flags)
+}
+
+func getsockname(fd int32) (IP, int, string, error) {
+	sa, err := syscall.Getsockname(int(fd))
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	return fromSockaddr(sa)
+}
+
+func getpeername(fd int32) (IP, int, string, error) {
+	sa, err := syscall.Getpeername(int(fd))
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	return fromSockaddr(sa)
+}
+
+func fromSockaddr(sa syscall.Sockaddr) (IP, int, string, error) {
+	switch addr := sa.(type) {
+	case *syscall.SockaddrInet4:
+		return IP(append([]byte{}, addr.Addr[:]...)), addr.Port, "", nil
+	case *syscall.SockaddrInet6:
+		zone := ""
+		if addr.ZoneId != 0 {
+			if ifi, err := net.InterfaceByIndex(int(addr.ZoneId)); err == nil {
+				zone = ifi.Name
+			} else {
+				zone = strconv.Itoa(int(addr.ZoneId))
+			}
+		}
+
+		return IP(append([]byte{}, addr.Addr[:]...)), addr.Port, zone, nil
+	default:
+		return nil, 0, "", errors.New("could not use non-IP socket address")
+	}
+}
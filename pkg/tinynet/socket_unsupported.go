@@ -0,0 +1,154 @@
+//go:build js || tinygo
+// +build js tinygo
+
+package tinynet
+
+import (
+	"github.com/alphahorizonio/unisockets/pkg/unisockets"
+)
+
+const (
+	sockDgram = int32(2)
+	shutRd    = int32(0)
+	shutWr    = int32(1)
+
+	msgOOB      = int32(0x1)
+	msgDontWait = int32(0x40)
+	msgPeek     = int32(0x2)
+	msgWaitAll  = int32(0x100)
+)
+
+func recvFrom(fd int32, b []byte) (int, IP, int, error) {
+	return 0, nil, 0, errUnsupported
+}
+
+func sendTo(fd int32, b []byte, ip IP, port int, zone string) error {
+	return errUnsupported
+}
+
+func closeSocket(fd int32) error {
+	return unisockets.Shutdown(fd, unisockets.SHUT_RDWR)
+}
+
+func socket6(sotype int32) (int32, error) {
+	return -1, errUnsupported
+}
+
+func bind6(fd int32, ip IP, port int, zone string) error {
+	return errUnsupported
+}
+
+func connect6(fd int32, ip IP, port int, zone string) error {
+	return errUnsupported
+}
+
+func accept6(fd int32) (int32, IP, int, error) {
+	return -1, nil, 0, errUnsupported
+}
+
+func isConnAlive(fd int32) bool {
+	// TODO: Currently connections are always assumed to be alive on this platform
+
+	return true
+}
+
+func socketPair() (int32, int32, error) {
+	return -1, -1, errUnsupported
+}
+
+func dupSocket(fd int32) (int32, error) {
+	return -1, errUnsupported
+}
+
+func isInProgress(err error) bool {
+	return false
+}
+
+func socketError(fd int32) error {
+	return errUnsupported
+}
+
+func socketUnix(sotype int32) (int32, error) {
+	return -1, errUnsupported
+}
+
+func bindUnix(fd int32, name string) error {
+	return errUnsupported
+}
+
+func connectUnix(fd int32, name string) error {
+	return errUnsupported
+}
+
+func acceptUnix(fd int32) (int32, string, error) {
+	return -1, "", errUnsupported
+}
+
+func recvFromUnix(fd int32, b []byte) (int, string, error) {
+	return 0, "", errUnsupported
+}
+
+func sendToUnix(fd int32, b []byte, name string) error {
+	return errUnsupported
+}
+
+func writev(fd int32, buffers [][]byte) (int64, error) {
+	// Vectored I/O is not available on this platform, so send the buffers one by one
+	written := int64(0)
+	for _, b := range buffers {
+		if len(b) == 0 {
+			continue
+		}
+
+		n, err := unisockets.Send(fd, b, 0)
+		if n > 0 {
+			written += int64(n)
+		}
+
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+func readv(fd int32, buffers [][]byte) (int64, error) {
+	// Vectored I/O is not available on this platform, so read into the first buffer only
+	for _, b := range buffers {
+		if len(b) == 0 {
+			continue
+		}
+
+		readMsg := make([]byte, len(b))
+
+		n, err := unisockets.Recv(fd, &readMsg, uint32(len(b)), 0)
+		if n > 0 {
+			copy(b, readMsg[:n])
+		}
+
+		if n < 0 {
+			return 0, err
+		}
+
+		return int64(n), err
+	}
+
+	return 0, nil
+}
+
+func recvMsg(fd int32, b []byte, oob []byte, flags int) (int, int, int, IP, int, error) {
+	return 0, 0, 0, nil, 0, errUnsupported
+}
+
+func sendMsg(fd int32, b []byte, oob []byte, flags int) (int, error) {
+	return 0, errUnsupported
+}
+
+func getsockname(fd int32) (IP, int, string, error) {
+	return nil, 0, "", errUnsupported
+}
+
+func getpeername(fd int32) (IP, int, string, error) {
+	return nil, 0, "", errUnsupported
+}
@@ -0,0 +1,207 @@
+//go:build linux && !js && !tinygo
+// +build linux,!js,!tinygo
+
+package tinynet
+
+import (
+	"encoding/binary"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	soReusePort     = 0xf // Not defined by package syscall on all architectures
+	soOriginalDst   = 0x50
+	ipv6Transparent = 0x4b
+	tcpFastOpen     = 0x17
+	msgFastOpen     = 0x20000000
+)
+
+func setReusePort(fd int32, reuse bool) error {
+	return syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, soReusePort, boolToInt(reuse))
+}
+
+func toKeepAliveSeconds(d time.Duration) int {
+	// Round up to the next full second
+	secs := int((d + time.Second - 1) / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+
+	return secs
+}
+
+func setKeepAlivePeriod(fd int32, d time.Duration) error {
+	if err := setKeepAliveInterval(fd, d); err != nil {
+		return err
+	}
+
+	return setKeepAliveIdle(fd, d)
+}
+
+func setKeepAliveIdle(fd int32, d time.Duration) error {
+	return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_TCP, syscall.TCP_KEEPIDLE, toKeepAliveSeconds(d))
+}
+
+func setKeepAliveInterval(fd int32, d time.Duration) error {
+	return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_TCP, syscall.TCP_KEEPINTVL, toKeepAliveSeconds(d))
+}
+
+func setKeepAliveCount(fd int32, count int) error {
+	return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_TCP, syscall.TCP_KEEPCNT, count)
+}
+
+func bindToDevice(fd int32, ifname string) error {
+	return syscall.BindToDevice(int(fd), ifname)
+}
+
+func setTransparent(fd int32, transparent bool, v6 bool) error {
+	if v6 {
+		return syscall.SetsockoptInt(int(fd), syscall.SOL_IPV6, ipv6Transparent, boolToInt(transparent))
+	}
+
+	return syscall.SetsockoptInt(int(fd), syscall.SOL_IP, syscall.IP_TRANSPARENT, boolToInt(transparent))
+}
+
+func originalDestination(fd int32, v6 bool) (IP, int, error) {
+	// The option returns a sockaddr, which fits into these structs
+	if v6 {
+		info, err := syscall.GetsockoptIPv6MTUInfo(int(fd), syscall.SOL_IPV6, soOriginalDst)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		port := (*[2]byte)(unsafe.Pointer(&info.Addr.Port))
+
+		return IP(append([]byte{}, info.Addr.Addr[:]...)), int(binary.BigEndian.Uint16(port[:])), nil
+	}
+
+	mreq, err := syscall.GetsockoptIPv6Mreq(int(fd), syscall.SOL_IP, soOriginalDst)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// struct sockaddr_in: family (2 bytes), port (2 bytes), address (4 bytes)
+	addr := mreq.Multiaddr
+
+	return IP{addr[4], addr[5], addr[6], addr[7]}, int(binary.BigEndian.Uint16(addr[2:4])), nil
+}
+
+func setFastOpen(fd int32, enabled bool) error {
+	// The value is the maximum number of pending fast open requests
+	qlen := 0
+	if enabled {
+		qlen = DefaultFastOpenQueueLength
+	}
+
+	return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_TCP, tcpFastOpen, qlen)
+}
+
+func sendFastOpen(fd int32, data []byte, ip IP, port int, zone string) error {
+	var addr syscall.Sockaddr
+	if isIPv6(ip) {
+		addr6, err := toSockaddrInet6(ip, port, zone)
+		if err != nil {
+			return err
+		}
+
+		addr = addr6
+	} else {
+		addr4 := &syscall.SockaddrInet4{Port: port}
+		copy(addr4.Addr[:], ip.To4())
+
+		addr = addr4
+	}
+
+	// Connects and sends the data with the SYN if a fast open cookie is available
+	return syscall.Sendto(int(fd), data, msgFastOpen, addr)
+}
+
+func setQuickAck(fd int32, quickAck bool) error {
+	return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_TCP, syscall.TCP_QUICKACK, boolToInt(quickAck))
+}
+
+func setMark(fd int32, mark uint32) error {
+	return syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_MARK, int(mark))
+}
+
+func peerCredentials(fd int32) (*Ucred, error) {
+	cred, err := syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ucred{
+		Pid: cred.Pid,
+		Uid: cred.Uid,
+		Gid: cred.Gid,
+	}, nil
+}
+
+func setCongestionControl(fd int32, algorithm string) error {
+	return syscall.SetsockoptString(int(fd), syscall.IPPROTO_TCP, syscall.TCP_CONGESTION, algorithm)
+}
+
+func getCongestionControl(fd int32) (string, error) {
+	name, err := unix.GetsockoptString(int(fd), syscall.IPPROTO_TCP, syscall.TCP_CONGESTION)
+	if err != nil {
+		return "", err
+	}
+
+	// The name is NUL-padded
+	if i := strings.IndexByte(name, 0); i >= 0 {
+		name = name[:i]
+	}
+
+	return name, nil
+}
+
+func getTCPInfo(fd int32) (*TCPInfo, error) {
+	info, err := unix.GetsockoptTCPInfo(int(fd), syscall.IPPROTO_TCP, syscall.TCP_INFO)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TCPInfo{
+		State:       info.State,
+		Retransmits: info.Total_retrans,
+		RttUs:       info.Rtt,
+		RttVarUs:    info.Rttvar,
+		RtoUs:       info.Rto,
+		Unacked:     info.Unacked,
+		Sacked:      info.Sacked,
+		Lost:        info.Lost,
+		SndMss:      info.Snd_mss,
+		SndCwnd:     info.Snd_cwnd,
+	}, nil
+}
+
+func setDontFragment(fd int32, dontFragment bool, v6 bool) error {
+	if v6 {
+		mode := syscall.IPV6_PMTUDISC_DONT
+		if dontFragment {
+			mode = syscall.IPV6_PMTUDISC_DO
+		}
+
+		return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_MTU_DISCOVER, mode)
+	}
+
+	mode := syscall.IP_PMTUDISC_DONT
+	if dontFragment {
+		mode = syscall.IP_PMTUDISC_DO
+	}
+
+	return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_MTU_DISCOVER, mode)
+}
+
+func getMTU(fd int32, v6 bool) (int, error) {
+	if v6 {
+		return syscall.GetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_MTU)
+	}
+
+	return syscall.GetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_MTU)
+}
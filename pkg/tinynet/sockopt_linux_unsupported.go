@@ -0,0 +1,86 @@
+//go:build !linux || js || tinygo
+// +build !linux js tinygo
+
+package tinynet
+
+import "time"
+
+func setReusePort(fd int32, reuse bool) error {
+	return errUnsupported
+}
+
+func setKeepAlivePeriod(fd int32, d time.Duration) error {
+	// TODO: Currently the system's default keep-alive period is used on this platform
+
+	return nil
+}
+
+func setKeepAliveIdle(fd int32, d time.Duration) error {
+	// TODO: Currently the system's default keep-alive idle time is used on this platform
+
+	return nil
+}
+
+func setKeepAliveInterval(fd int32, d time.Duration) error {
+	// TODO: Currently the system's default keep-alive interval is used on this platform
+
+	return nil
+}
+
+func setKeepAliveCount(fd int32, count int) error {
+	// TODO: Currently the system's default keep-alive probe count is used on this platform
+
+	return nil
+}
+
+func bindToDevice(fd int32, ifname string) error {
+	return errUnsupported
+}
+
+func setTransparent(fd int32, transparent bool, v6 bool) error {
+	return errUnsupported
+}
+
+func originalDestination(fd int32, v6 bool) (IP, int, error) {
+	return nil, 0, errUnsupported
+}
+
+func setFastOpen(fd int32, enabled bool) error {
+	return errUnsupported
+}
+
+func sendFastOpen(fd int32, data []byte, ip IP, port int, zone string) error {
+	return errUnsupported
+}
+
+func setQuickAck(fd int32, quickAck bool) error {
+	return errUnsupported
+}
+
+func setMark(fd int32, mark uint32) error {
+	return errUnsupported
+}
+
+func peerCredentials(fd int32) (*Ucred, error) {
+	return nil, errUnsupported
+}
+
+func setCongestionControl(fd int32, algorithm string) error {
+	return errUnsupported
+}
+
+func getCongestionControl(fd int32) (string, error) {
+	return "", errUnsupported
+}
+
+func getTCPInfo(fd int32) (*TCPInfo, error) {
+	return nil, errUnsupported
+}
+
+func setDontFragment(fd int32, dontFragment bool, v6 bool) error {
+	return errUnsupported
+}
+
+func getMTU(fd int32, v6 bool) (int, error) {
+	return 0, errUnsupported
+}
@@ -0,0 +1,176 @@
+//go:build !js && !tinygo
+// +build !js,!tinygo
+
+package tinynet
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"time"
+)
+
+func setTimeout(fd int32, opt int, t time.Time) error {
+	// A zero timeval disables the timeout
+	timeout := syscall.Timeval{}
+
+	if !t.IsZero() {
+		d := time.Until(t)
+		if d < time.Microsecond {
+			// The deadline has already passed; use the smallest non-zero timeout
+			d = time.Microsecond
+		}
+
+		timeout = syscall.NsecToTimeval(d.Nanoseconds())
+	}
+
+	return syscall.SetsockoptTimeval(int(fd), syscall.SOL_SOCKET, opt, &timeout)
+}
+
+func setReadTimeout(fd int32, t time.Time) error {
+	return setTimeout(fd, syscall.SO_RCVTIMEO, t)
+}
+
+func setWriteTimeout(fd int32, t time.Time) error {
+	return setTimeout(fd, syscall.SO_SNDTIMEO, t)
+}
+
+func isTimeout(err error) bool {
+	errno, ok := err.(syscall.Errno)
+
+	// Connect returns EINPROGRESS once the send timeout has elapsed
+	return ok && (errno == syscall.EAGAIN || errno == syscall.EWOULDBLOCK || errno == syscall.EINPROGRESS)
+}
+
+func boolToInt(v bool) int {
+	if v {
+		return 1
+	}
+
+	return 0
+}
+
+func setReuseAddr(fd int32, reuse bool) error {
+	return syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, boolToInt(reuse))
+}
+
+func setKeepAlive(fd int32, keepAlive bool) error {
+	return syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_KEEPALIVE, boolToInt(keepAlive))
+}
+
+func setNonblock(fd int32, nonblocking bool) error {
+	return syscall.SetNonblock(int(fd), nonblocking)
+}
+
+func setNoDelay(fd int32, noDelay bool) error {
+	return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_TCP, syscall.TCP_NODELAY, boolToInt(noDelay))
+}
+
+func setOOBInline(fd int32, inline bool) error {
+	return syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_OOBINLINE, boolToInt(inline))
+}
+
+func interfaceIPv4(ifi *net.Interface) ([4]byte, error) {
+	addr := [4]byte{}
+	if ifi == nil {
+		return addr, nil
+	}
+
+	addrs, err := ifi.Addrs()
+	if err != nil {
+		return addr, err
+	}
+
+	for _, candidate := range addrs {
+		if ipNet, ok := candidate.(*net.IPNet); ok {
+			if ip4 := ipNet.IP.To4(); ip4 != nil {
+				copy(addr[:], ip4)
+
+				return addr, nil
+			}
+		}
+	}
+
+	return addr, errors.New("could not find IPv4 address for interface")
+}
+
+func setMembership(fd int32, ifi *net.Interface, group net.IP, v4Opt, v6Opt int) error {
+	if ip4 := group.To4(); ip4 != nil {
+		mreq := &syscall.IPMreq{}
+		copy(mreq.Multiaddr[:], ip4)
+
+		iface, err := interfaceIPv4(ifi)
+		if err != nil {
+			return err
+		}
+		mreq.Interface = iface
+
+		return syscall.SetsockoptIPMreq(int(fd), syscall.IPPROTO_IP, v4Opt, mreq)
+	}
+
+	mreq := &syscall.IPv6Mreq{}
+	copy(mreq.Multiaddr[:], group.To16())
+	if ifi != nil {
+		mreq.Interface = uint32(ifi.Index)
+	}
+
+	return syscall.SetsockoptIPv6Mreq(int(fd), syscall.IPPROTO_IPV6, v6Opt, mreq)
+}
+
+func joinGroup(fd int32, ifi *net.Interface, group net.IP) error {
+	return setMembership(fd, ifi, group, syscall.IP_ADD_MEMBERSHIP, syscall.IPV6_JOIN_GROUP)
+}
+
+func leaveGroup(fd int32, ifi *net.Interface, group net.IP) error {
+	return setMembership(fd, ifi, group, syscall.IP_DROP_MEMBERSHIP, syscall.IPV6_LEAVE_GROUP)
+}
+
+func setMulticastTTL(fd int32, ttl int, v6 bool) error {
+	if v6 {
+		return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_MULTICAST_HOPS, ttl)
+	}
+
+	return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_MULTICAST_TTL, ttl)
+}
+
+func setMulticastLoopback(fd int32, loopback bool, v6 bool) error {
+	if v6 {
+		return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_MULTICAST_LOOP, boolToInt(loopback))
+	}
+
+	return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_MULTICAST_LOOP, boolToInt(loopback))
+}
+
+func setTOS(fd int32, tos int, v6 bool) error {
+	if v6 {
+		return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_TCLASS, tos)
+	}
+
+	return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_TOS, tos)
+}
+
+func setSendBuffer(fd int32, bytes int) error {
+	return syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_SNDBUF, bytes)
+}
+
+func setReceiveBuffer(fd int32, bytes int) error {
+	return syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_RCVBUF, bytes)
+}
+
+func getSendBuffer(fd int32) (int, error) {
+	return syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_SNDBUF)
+}
+
+func getReceiveBuffer(fd int32) (int, error) {
+	return syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_RCVBUF)
+}
+
+func setLinger(fd int32, sec int) error {
+	linger := syscall.Linger{}
+	if sec >= 0 {
+		linger.Onoff = 1
+		linger.Linger = int32(sec)
+	}
+
+	return syscall.SetsockoptLinger(int(fd), syscall.SOL_SOCKET, syscall.SO_LINGER, &linger)
+}
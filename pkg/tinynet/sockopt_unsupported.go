@@ -0,0 +1,91 @@
+//go:build js || tinygo
+// +build js tinygo
+
+package tinynet
+
+import (
+	"net"
+	"time"
+)
+
+func setReadTimeout(fd int32, t time.Time) error {
+	// TODO: Currently there is an infinite deadline on this platform
+
+	return nil
+}
+
+func setWriteTimeout(fd int32, t time.Time) error {
+	// TODO: Currently there is an infinite deadline on this platform
+
+	return nil
+}
+
+func isTimeout(err error) bool {
+	return false
+}
+
+func setReuseAddr(fd int32, reuse bool) error {
+	// Addresses can always be reused on this platform
+
+	return nil
+}
+
+func setKeepAlive(fd int32, keepAlive bool) error {
+	// TODO: Currently keep-alives are not supported on this platform
+
+	return nil
+}
+
+func setNonblock(fd int32, nonblocking bool) error {
+	return errUnsupported
+}
+
+func setNoDelay(fd int32, noDelay bool) error {
+	// TODO: Currently Nagle's algorithm can't be configured on this platform
+
+	return nil
+}
+
+func setOOBInline(fd int32, inline bool) error {
+	return errUnsupported
+}
+
+func joinGroup(fd int32, ifi *net.Interface, group net.IP) error {
+	return errUnsupported
+}
+
+func leaveGroup(fd int32, ifi *net.Interface, group net.IP) error {
+	return errUnsupported
+}
+
+func setMulticastTTL(fd int32, ttl int, v6 bool) error {
+	return errUnsupported
+}
+
+func setMulticastLoopback(fd int32, loopback bool, v6 bool) error {
+	return errUnsupported
+}
+
+func setTOS(fd int32, tos int, v6 bool) error {
+	return errUnsupported
+}
+
+func setSendBuffer(fd int32, bytes int) error {
+	return errUnsupported
+}
+
+func setReceiveBuffer(fd int32, bytes int) error {
+	return errUnsupported
+}
+
+func getSendBuffer(fd int32) (int, error) {
+	return 0, errUnsupported
+}
+
+func getReceiveBuffer(fd int32) (int, error) {
+	return 0, errUnsupported
+}
+
+func setLinger(fd int32, sec int) error {
+	return errUnsupported
+}
@@ -0,0 +1,18 @@
+package tinynet
+
+import "io"
+
+// Moves data from src to dst until src reaches EOF; on Linux the data never
+// leaves the kernel, elsewhere it is copied through user space.
+func Splice(dst, src *TCPConn) (int64, error) {
+	if dst.isClosed() || src.isClosed() {
+		return 0, wrapError(errClosed)
+	}
+
+	if n, handled, err := spliceSockets(dst.fd, src.fd); handled {
+		return n, wrapError(err)
+	}
+
+	// Disconnects end the copy like io.EOF
+	return io.Copy(dst, NewEOFReader(src))
+}
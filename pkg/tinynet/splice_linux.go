@@ -0,0 +1,75 @@
+//go:build linux && !js && !tinygo
+// +build linux,!js,!tinygo
+
+package tinynet
+
+import (
+	"os"
+	"syscall"
+)
+
+const (
+	spliceMove = 0x1
+	spliceMore = 0x4
+
+	// Matches the default pipe capacity, so a splice into the pipe never has to wait for it to drain
+	maxSpliceSize = 1 << 16
+)
+
+func spliceSockets(dst, src int32) (int64, bool, error) {
+	// Data can only be spliced to and from a pipe, so it has to go through one
+	var p [2]int
+	if err := syscall.Pipe2(p[:], syscall.O_CLOEXEC); err != nil {
+		return 0, false, nil
+	}
+	defer syscall.Close(p[0])
+	defer syscall.Close(p[1])
+
+	written := int64(0)
+	for {
+		n, err := syscall.Splice(int(src), nil, p[1], nil, maxSpliceSize, spliceMove|spliceMore)
+		if err == syscall.EINTR {
+			continue
+		}
+
+		if err != nil {
+			// Fall back to copying if the sockets don't support splice
+			if written == 0 && (err == syscall.EINVAL || err == syscall.ENOSYS) {
+				return 0, false, nil
+			}
+
+			if isTimeout(err) {
+				return written, true, os.ErrDeadlineExceeded
+			}
+
+			return written, true, err
+		}
+
+		// EOF
+		if n == 0 {
+			return written, true, nil
+		}
+
+		// Drain the pipe into the destination; the count's type depends on the architecture
+		pending := int64(n)
+		for pending > 0 {
+			m, err := syscall.Splice(p[0], nil, int(dst), nil, int(pending), spliceMove|spliceMore)
+			if m > 0 {
+				written += int64(m)
+				pending -= int64(m)
+			}
+
+			if err == syscall.EINTR {
+				continue
+			}
+
+			if err != nil {
+				if isTimeout(err) {
+					return written, true, os.ErrDeadlineExceeded
+				}
+
+				return written, true, err
+			}
+		}
+	}
+}
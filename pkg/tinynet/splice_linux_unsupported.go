@@ -0,0 +1,10 @@
+//go:build !linux || js || tinygo
+// +build !linux js tinygo
+
+package tinynet
+
+func spliceSockets(dst, src int32) (int64, bool, error) {
+	// Data is always copied through user space on this platform
+
+	return 0, false, nil
+}
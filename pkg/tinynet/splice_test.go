@@ -0,0 +1,91 @@
+package tinynet
+
+import (
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+const splicePayloadSize = 4 * 1024 * 1024
+
+// Sends size bytes to every connection, then closes it
+func startSourceServer(tb testing.TB, size int) string {
+	tb.Helper()
+
+	l, addr := listenLoopback(tb)
+	payload := make([]byte, size)
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				_, _ = conn.Write(payload)
+			}()
+		}
+	}()
+
+	return addr
+}
+
+func startDiscardServer(tb testing.TB) string {
+	tb.Helper()
+
+	l, addr := listenLoopback(tb)
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				_, _ = io.Copy(ioutil.Discard, NewEOFReader(conn))
+			}()
+		}
+	}()
+
+	return addr
+}
+
+func benchmarkForward(b *testing.B, forward func(dst, src *TCPConn) (int64, error)) {
+	srcAddr := startSourceServer(b, splicePayloadSize)
+	dst := dialLoopback(b, startDiscardServer(b)).(TCPConn)
+
+	b.SetBytes(splicePayloadSize)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		src := dialLoopback(b, srcAddr).(TCPConn)
+
+		n, err := forward(&dst, &src)
+		if err != nil {
+			b.Fatal("could not forward:", err)
+		}
+
+		if n != splicePayloadSize {
+			b.Fatalf("forwarded %v bytes, expected %v", n, splicePayloadSize)
+		}
+
+		_ = src.Close()
+	}
+}
+
+func BenchmarkSplice(b *testing.B) {
+	benchmarkForward(b, Splice)
+}
+
+func BenchmarkSpliceCopy(b *testing.B) {
+	benchmarkForward(b, func(dst, src *TCPConn) (int64, error) {
+		// Hide ReadFrom and WriteTo so that the data is copied through user space
+		return io.Copy(writerOnly{dst}, NewEOFReader(src))
+	})
+}
@@ -0,0 +1,67 @@
+package tinynet
+
+import (
+	"net"
+	"sync/atomic"
+)
+
+type ConnStats struct {
+	BytesRead    int64
+	BytesWritten int64
+	ReadCalls    int64
+	WriteCalls   int64
+}
+
+type StatsConn struct {
+	// Accessed atomically; kept first for 64-bit alignment on 32-bit platforms
+	bytesRead    int64
+	bytesWritten int64
+	readCalls    int64
+	writeCalls   int64
+
+	net.Conn
+}
+
+func NewStatsConn(inner net.Conn) *StatsConn {
+	return &StatsConn{
+		Conn: inner,
+	}
+}
+
+func (c *StatsConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+
+	atomic.AddInt64(&c.readCalls, 1)
+	if n > 0 {
+		atomic.AddInt64(&c.bytesRead, int64(n))
+	}
+
+	return n, err
+}
+
+func (c *StatsConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+
+	atomic.AddInt64(&c.writeCalls, 1)
+	if n > 0 {
+		atomic.AddInt64(&c.bytesWritten, int64(n))
+	}
+
+	return n, err
+}
+
+func (c *StatsConn) Snapshot() ConnStats {
+	return ConnStats{
+		BytesRead:    atomic.LoadInt64(&c.bytesRead),
+		BytesWritten: atomic.LoadInt64(&c.bytesWritten),
+		ReadCalls:    atomic.LoadInt64(&c.readCalls),
+		WriteCalls:   atomic.LoadInt64(&c.writeCalls),
+	}
+}
+
+func (c *StatsConn) Reset() {
+	atomic.StoreInt64(&c.bytesRead, 0)
+	atomic.StoreInt64(&c.bytesWritten, 0)
+	atomic.StoreInt64(&c.readCalls, 0)
+	atomic.StoreInt64(&c.writeCalls, 0)
+}
@@ -0,0 +1,25 @@
+package tinynet
+
+// A subset of the kernel's tcp_info
+type TCPInfo struct {
+	State uint8
+	// Retransmits over the lifetime of the connection
+	Retransmits uint32
+	RttUs       uint32
+	RttVarUs    uint32
+	RtoUs       uint32
+	// Segments which have been sent but not yet acknowledged
+	Unacked uint32
+	Sacked  uint32
+	Lost    uint32
+	SndMss  uint32
+	SndCwnd uint32
+}
+
+func (c TCPConn) TCPInfo() (*TCPInfo, error) {
+	if c.isClosed() {
+		return nil, wrapError(errClosed)
+	}
+
+	return getTCPInfo(c.fd)
+}
@@ -0,0 +1,23 @@
+package tinynet
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestTCPInfo(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("TCP info can only be read on Linux")
+	}
+
+	conn, _ := dialPeer(t)
+
+	info, err := conn.TCPInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.SndMss == 0 {
+		t.Fatal("got an MSS of 0")
+	}
+}
@@ -0,0 +1,158 @@
+package tinynet
+
+import (
+	"io"
+	"net"
+	"sync"
+)
+
+const (
+	tapQueueLength = 128
+)
+
+type teeConn struct {
+	net.Conn
+
+	r io.Writer
+	w io.Writer
+}
+
+func NewTeeConn(inner net.Conn, r io.Writer, w io.Writer) net.Conn {
+	return &teeConn{
+		Conn: inner,
+		r:    r,
+		w:    w,
+	}
+}
+
+func (c *teeConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 && c.r != nil {
+		// Tee errors must not interrupt the connection
+		if _, err := c.r.Write(b[:n]); err != nil {
+			getLogger().Error("could not tee read bytes", "raddr", c.RemoteAddr(), "err", err)
+		}
+	}
+
+	return n, err
+}
+
+func (c *teeConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 && c.w != nil {
+		// Tee errors must not interrupt the connection
+		if _, err := c.w.Write(b[:n]); err != nil {
+			getLogger().Error("could not tee written bytes", "raddr", c.RemoteAddr(), "err", err)
+		}
+	}
+
+	return n, err
+}
+
+type teeListener struct {
+	net.Listener
+
+	tap func(net.Conn)
+}
+
+// The tap gets a connection which yields a copy of the bytes read from each
+// accepted connection; it is closed when the accepted connection is.
+func TeeListener(inner net.Listener, tap func(net.Conn)) net.Listener {
+	return &teeListener{
+		Listener: inner,
+		tap:      tap,
+	}
+}
+
+func (l *teeListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	tapConn, feed := net.Pipe()
+	w := newTapWriter(feed)
+
+	go l.tap(tapConn)
+
+	return &tappedConn{
+		Conn: NewTeeConn(conn, w, nil),
+		w:    w,
+	}, nil
+}
+
+type tappedConn struct {
+	net.Conn
+
+	w *tapWriter
+}
+
+func (c *tappedConn) Close() error {
+	c.w.Close()
+
+	return c.Conn.Close()
+}
+
+// Copies are handed to the tap asynchronously so that a slow tap can't block the connection
+type tapWriter struct {
+	feed   net.Conn
+	queue  chan []byte
+	closed bool
+	lock   sync.Mutex
+}
+
+func newTapWriter(feed net.Conn) *tapWriter {
+	w := &tapWriter{
+		feed:  feed,
+		queue: make(chan []byte, tapQueueLength),
+	}
+
+	go w.writeLoop()
+
+	return w
+}
+
+func (w *tapWriter) Write(b []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.closed {
+		return len(b), nil
+	}
+
+	msg := make([]byte, len(b))
+	copy(msg, b)
+
+	select {
+	case w.queue <- msg:
+	default:
+		getLogger().Error("could not tap read bytes: tap is too slow")
+	}
+
+	return len(b), nil
+}
+
+func (w *tapWriter) Close() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if !w.closed {
+		w.closed = true
+
+		close(w.queue)
+	}
+}
+
+func (w *tapWriter) writeLoop() {
+	defer w.feed.Close()
+
+	for msg := range w.queue {
+		if _, err := w.feed.Write(msg); err != nil {
+			// The tap went away; drop the remaining copies
+			for range w.queue {
+			}
+
+			return
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package tinynet
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestTeeConn(t *testing.T) {
+	read, written := &bytes.Buffer{}, &bytes.Buffer{}
+	conn := NewTeeConn(dialLoopback(t, startEchoServer(t)), read, written)
+
+	expected := []byte("hello")
+	if _, err := conn.Write(expected); err != nil {
+		t.Fatal(err)
+	}
+
+	actual := make([]byte, len(expected))
+	if _, err := io.ReadFull(conn, actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(written.Bytes(), expected) {
+		t.Fatalf("captured %q as written, expected %q", written.Bytes(), expected)
+	}
+
+	if !bytes.Equal(read.Bytes(), expected) {
+		t.Fatalf("captured %q as read, expected %q", read.Bytes(), expected)
+	}
+}
@@ -1,18 +1,47 @@
 package tinynet
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
+	"fmt"
+	"io"
 	"net"
+	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/alphahorizonio/unisockets/pkg/unisockets"
 )
 
+var (
+	errUnsupported  = errors.New("operation not supported on this platform")
+	errDisconnected = errors.New("client disconnected")
+	errClosed       = errors.New("use of closed network connection")
+	errWouldBlock   = errors.New("operation would block")
+)
+
 type IP []byte
 
+func (ip IP) To4() IP {
+	return IP(net.IP(ip).To4())
+}
+
+func (ip IP) To16() IP {
+	return IP(net.IP(ip).To16())
+}
+
+func (ip IP) Equal(other IP) bool {
+	return net.IP(ip).Equal(net.IP(other))
+}
+
+func (ip IP) String() string {
+	return net.IP(ip).String()
+}
+
 type TCPAddr struct {
 	stringAddr string
 
@@ -25,91 +54,270 @@ func (t *TCPAddr) Network() string {
 	return "tcp"
 }
 
+func (t *TCPAddr) Equal(other *TCPAddr) bool {
+	if t == nil || other == nil {
+		return t == other
+	}
+
+	return t.IP.To16().Equal(other.IP.To16()) && t.Port == other.Port && t.Zone == other.Zone
+}
+
 func (t *TCPAddr) String() string {
+	if t.stringAddr == "" {
+		return formatAddr(t.IP, t.Port, t.Zone)
+	}
+
 	return t.stringAddr
 }
 
 func ResolveTCPAddr(network, address string) (*TCPAddr, error) {
-	parts := strings.Split(address, ":")
+	return DefaultResolver.ResolveTCPAddr(context.Background(), network, address)
+}
+
+func splitAddr(network, address string) (string, int, error) {
+	host, rawPort, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", 0, errors.New("could not parse address")
+	}
+
+	port, err := strconv.Atoi(rawPort)
+	if err != nil {
+		// Named service ports (e.g. "http")
+		port, err = net.LookupPort(network, rawPort)
+		if err != nil {
+			return "", 0, errors.New("could not parse port")
+		}
+	}
+
+	if port < 0 || port > 65535 {
+		return "", 0, errors.New("could not parse port")
+	}
+
+	return host, port, nil
+}
+
+func parseIP(host string) (IP, string, error) {
+	// Unspecified (0.0.0.0)
+	if host == "" {
+		return IP{0, 0, 0, 0}, "", nil
+	}
+
+	// IPv6 (xxxx:xxxx::xxxx%zone)
+	if strings.Contains(host, ":") {
+		zone := ""
+		if i := strings.LastIndex(host, "%"); i != -1 {
+			host, zone = host[:i], host[i+1:]
+		}
+
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return nil, "", errors.New("could not parse IP")
+		}
+
+		return IP(ip.To16()), zone, nil
+	}
 
 	ip := make([]byte, 4) // xxx.xxx.xxx.xxx
-	for i, part := range strings.Split(parts[0], ".") {
+	parts := strings.Split(host, ".")
+	if len(parts) != len(ip) {
+		return nil, "", errors.New("could not parse IP")
+	}
+
+	for i, part := range parts {
 		innerPart, err := strconv.Atoi(part)
-		if err != nil {
-			return nil, errors.New("could not parse IP")
+		if err != nil || innerPart < 0 || innerPart > 255 {
+			return nil, "", errors.New("could not parse IP")
 		}
 
 		ip[i] = byte(innerPart)
 	}
 
-	port, err := strconv.Atoi(parts[1])
-	if err != nil {
-		return nil, errors.New("could not parse port")
+	return ip, "", nil
+}
+
+func formatAddr(ip IP, port int, zone string) string {
+	host := net.IP(ip).String()
+	if zone != "" {
+		host += "%" + zone
 	}
 
-	return &TCPAddr{
-		stringAddr: address,
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
 
-		IP:   ip,
-		Port: port,
-		Zone: "",
-	}, nil
+func addrString(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+
+	if tcpAddr, ok := addr.(*TCPAddr); ok && tcpAddr == nil {
+		return ""
+	}
+
+	return addr.String()
 }
 
-func Listen(network, address string) (net.Listener, error) {
-	laddr, err := ResolveTCPAddr(network, address)
-	if err != nil {
-		return TCPListener{}, err
+func isIPv6(ip IP) bool {
+	return len(ip) == net.IPv6len && net.IP(ip).To4() == nil
+}
+
+func checkTCPNetwork(network string) error {
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+		return nil
+	default:
+		return fmt.Errorf("unsupported network %v", network)
 	}
+}
 
-	return ListenTCP(network, laddr)
+func checkUDPNetwork(network string) error {
+	switch network {
+	case "udp", "udp4", "udp6":
+		return nil
+	default:
+		return fmt.Errorf("unsupported network %v", network)
+	}
 }
 
-func ListenTCP(network string, laddr *TCPAddr) (*TCPListener, error) {
-	// Create address
-	serverAddress := unisockets.SockaddrIn{
+func ipForNetwork(network string, ip IP) (IP, error) {
+	switch network {
+	case "tcp4", "udp4":
+		if isIPv6(ip) {
+			return nil, fmt.Errorf("could not use IPv6 address %v with network %v", ip, network)
+		}
+	case "tcp6", "udp6":
+		if !isIPv6(ip) {
+			// Use all IPv6 addresses instead of all IPv4 addresses
+			if len(ip) == 0 || net.IP(ip).IsUnspecified() {
+				return IP(net.IPv6unspecified), nil
+			}
+
+			return nil, fmt.Errorf("could not use IPv4 address %v with network %v", ip, network)
+		}
+	}
+
+	return ip, nil
+}
+
+func toSockaddrIn(ip IP, port int) unisockets.SockaddrIn {
+	return unisockets.SockaddrIn{
 		SinFamily: unisockets.PF_INET,
-		SinPort:   unisockets.Htons(uint16(laddr.Port)),
+		SinPort:   unisockets.Htons(uint16(port)),
 		SinAddr: struct{ SAddr uint32 }{
-			SAddr: binary.LittleEndian.Uint32(laddr.IP),
+			SAddr: binary.LittleEndian.Uint32(net.IP(ip).To4()),
 		},
 	}
+}
+
+func ipFromSockaddrIn(addr unisockets.SockaddrIn) IP {
+	return IP{byte(addr.SinAddr.SAddr), byte(addr.SinAddr.SAddr >> 8), byte(addr.SinAddr.SAddr >> 16), byte(addr.SinAddr.SAddr >> 24)}
+}
+
+func portFromSockaddrIn(addr unisockets.SockaddrIn) int {
+	return int(unisockets.Htons(addr.SinPort))
+}
+
+func newSocket(ip IP, socketType int32) (int32, error) {
+	if isIPv6(ip) {
+		return socket6(socketType)
+	}
+
+	return unisockets.Socket(unisockets.PF_INET, socketType, 0)
+}
+
+func bindSocket(fd int32, ip IP, port int, zone string) error {
+	if isIPv6(ip) {
+		return bind6(fd, ip, port, zone)
+	}
+
+	socketAddress := toSockaddrIn(ip, port)
+
+	return unisockets.Bind(fd, &socketAddress)
+}
+
+func connectSocket(fd int32, ip IP, port int, zone string) error {
+	if isIPv6(ip) {
+		return connect6(fd, ip, port, zone)
+	}
+
+	socketAddress := toSockaddrIn(ip, port)
 
-	// Create socket
-	serverSocket, err := unisockets.Socket(unisockets.PF_INET, unisockets.SOCK_STREAM, 0)
+	return unisockets.Connect(fd, &socketAddress)
+}
+
+func Listen(network, address string) (net.Listener, error) {
+	l, err := NewListenConfig().Listen(context.Background(), network, address)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
 	}
 
-	// Bind
-	if err := unisockets.Bind(serverSocket, &serverAddress); err != nil {
-		return nil, err
+	return l, nil
+}
+
+func ListenPacket(network, address string) (net.PacketConn, error) {
+	switch network {
+	case "udp", "udp4", "udp6":
+		laddr, err := ResolveUDPAddr(network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := ListenUDP(network, laddr)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+
+		return conn, nil
+	default:
+		return nil, fmt.Errorf("unsupported network %v", network)
 	}
+}
 
-	// Listen
-	if err := unisockets.Listen(serverSocket, 5); err != nil {
-		return nil, err
+func ListenTCP(network string, laddr *TCPAddr) (*TCPListener, error) {
+	l, err := NewListenConfig().listenTCP(network, laddr)
+	if err != nil {
+		return nil, wrapError(err)
 	}
 
-	return &TCPListener{
-		fd:   serverSocket,
-		addr: laddr,
-	}, nil
+	return l, nil
 }
 
 type TCPListener struct {
-	fd   int32
-	addr net.Addr
+	fd      int32
+	network string
+	addr    net.Addr
+	closed  *int32
+	tracker *ConnectionTracker
+	logger  Logger
 }
 
 func (t TCPListener) Close() error {
-	return unisockets.Shutdown(t.fd, unisockets.SHUT_RDWR)
+	if t.closed == nil {
+		return wrapError(unisockets.Shutdown(t.fd, unisockets.SHUT_RDWR))
+	}
+
+	// Subsequent closes are no-ops
+	if !atomic.CompareAndSwapInt32(t.closed, 0, 1) {
+		return nil
+	}
+
+	t.log().Info("closed listener", "addr", t.addr)
+
+	return shutdownAndClose(t.fd)
+}
+
+func (t TCPListener) isClosed() bool {
+	return t.closed != nil && atomic.LoadInt32(t.closed) == 1
 }
 
 func (t TCPListener) Addr() net.Addr {
 	return t.addr
 }
 
+func (t TCPListener) SetDeadline(tm time.Time) error {
+	// Accept honors the receive timeout of the listening socket
+	return setReadTimeout(t.fd, tm)
+}
+
 func (l TCPListener) Accept() (net.Conn, error) {
 	conn, err := l.AcceptTCP()
 
@@ -117,16 +325,90 @@ func (l TCPListener) Accept() (net.Conn, error) {
 }
 
 func (l *TCPListener) AcceptTCP() (*TCPConn, error) {
+	// The fd might already belong to another socket
+	if l.isClosed() {
+		return nil, wrapError(errClosed)
+	}
+
+	var (
+		conn *TCPConn
+		err  error
+	)
+	if isIPv6(l.addr.(*TCPAddr).IP) {
+		conn, err = l.acceptTCP6()
+	} else {
+		conn, err = l.acceptTCP4()
+	}
+	if err != nil {
+		if !l.isClosed() && !errors.Is(err, os.ErrDeadlineExceeded) {
+			l.log().Error("could not accept connection", "addr", l.addr, "err", err)
+		}
+
+		return nil, err
+	}
+
+	conn.state.logger = l.logger
+
+	// Shutdown waits for accepted connections to be closed
+	if l.tracker != nil {
+		l.tracker.trackTCP(conn)
+	}
+
+	conn.log().Debug("accepted connection", "laddr", l.addr, "raddr", conn.raddr)
+
+	return conn, nil
+}
+
+// Overrides the package's logger; has to be set before the listener is used
+func (l *TCPListener) SetLogger(logger Logger) {
+	l.logger = logger
+}
+
+func (l TCPListener) log() Logger {
+	if l.logger != nil {
+		return l.logger
+	}
+
+	return getLogger()
+}
+
+// Stops accepting new connections and waits for the accepted ones to be
+// closed; once ctx is done, the remaining ones are closed forcibly.
+func (l *TCPListener) Shutdown(ctx context.Context) error {
+	var closeErr error
+	if !l.isClosed() {
+		closeErr = l.Close()
+	}
+
+	if l.tracker == nil {
+		return closeErr
+	}
+
+	if err := l.tracker.WaitIdle(ctx); err != nil {
+		_ = l.tracker.CloseAll()
+
+		return err
+	}
+
+	return closeErr
+}
+
+func (l *TCPListener) acceptTCP4() (*TCPConn, error) {
 	clientAddress := unisockets.SockaddrIn{}
 
 	// Accept
 	clientSocket, err := unisockets.Accept(l.fd, &clientAddress)
 	if err != nil {
-		return nil, err
+		if isTimeout(err) {
+			return nil, wrapError(os.ErrDeadlineExceeded)
+		}
+
+		return nil, wrapError(err)
 	}
 
 	return &TCPConn{
-		fd: clientSocket,
+		fd:      clientSocket,
+		network: l.network,
 		laddr: &TCPAddr{
 			stringAddr: "",
 			IP:         l.addr.(*TCPAddr).IP,
@@ -135,110 +417,656 @@ func (l *TCPListener) AcceptTCP() (*TCPConn, error) {
 		},
 		raddr: &TCPAddr{
 			stringAddr: "",
-			IP:         IP{byte(clientAddress.SinAddr.SAddr), byte(clientAddress.SinAddr.SAddr >> 8), byte(clientAddress.SinAddr.SAddr >> 16), byte(clientAddress.SinAddr.SAddr >> 24)},
-			Port:       int(clientAddress.SinPort),
+			IP:         ipFromSockaddrIn(clientAddress),
+			Port:       portFromSockaddrIn(clientAddress),
 			Zone:       "",
 		},
+		state: &connState{},
+	}, nil
+}
+
+func (l *TCPListener) acceptTCP6() (*TCPConn, error) {
+	// Accept
+	clientSocket, ip, port, err := accept6(l.fd)
+	if err != nil {
+		if isTimeout(err) {
+			return nil, wrapError(os.ErrDeadlineExceeded)
+		}
+
+		return nil, wrapError(err)
+	}
+
+	return &TCPConn{
+		fd:      clientSocket,
+		network: l.network,
+		laddr: &TCPAddr{
+			stringAddr: "",
+			IP:         l.addr.(*TCPAddr).IP,
+			Port:       l.addr.(*TCPAddr).Port,
+			Zone:       l.addr.(*TCPAddr).Zone,
+		},
+		raddr: &TCPAddr{
+			stringAddr: "",
+			IP:         ip,
+			Port:       port,
+			Zone:       "",
+		},
+		state: &connState{},
 	}, nil
 }
 
 func Dial(network, address string) (net.Conn, error) {
-	raddr, err := ResolveTCPAddr(network, address)
+	conn, err := NewDialConfig().Dial(context.Background(), network, address)
+
+	return conn, wrapError(err)
+}
+
+func DialFrom(network, laddr, raddr string) (net.Conn, error) {
+	conn, err := NewDialConfig().dial(context.Background(), network, laddr, raddr)
+
+	return conn, wrapError(err)
+}
+
+func DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	dc := NewDialConfig()
+	dc.Timeout = timeout
+
+	conn, err := dc.Dial(context.Background(), network, address)
+
+	return conn, wrapError(err)
+}
+
+func DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := NewDialConfig().Dial(ctx, network, address)
+
+	return conn, wrapError(err)
+}
+
+func DialTCP(network string, laddr, raddr *TCPAddr) (*TCPConn, error) {
+	conn, err := NewDialConfig().dialTCP(context.Background(), network, laddr, raddr)
+
+	return conn, wrapError(err)
+}
+
+func DialTCPWithFastOpen(network string, laddr, raddr *TCPAddr, data []byte) (*TCPConn, error) {
+	dc := NewDialConfig()
+	dc.fastOpenData = data
+
+	conn, err := dc.dialTCP(context.Background(), network, laddr, raddr)
+
+	return conn, wrapError(err)
+}
+
+type TCPConn struct {
+	fd      int32
+	network string
+
+	laddr net.Addr
+	raddr net.Addr
+
+	state *connState
+}
+
+type connState struct {
+	closed    int32
+	closeOnce sync.Once
+
+	laddr     net.Addr
+	laddrOnce sync.Once
+	raddr     net.Addr
+	raddrOnce sync.Once
+
+	// Called once the connection has been closed
+	onClose func()
+
+	logger Logger
+}
+
+func (c TCPConn) isClosed() bool {
+	return c.state != nil && atomic.LoadInt32(&c.state.closed) == 1
+}
+
+func (c TCPConn) Read(b []byte) (int, error) {
+	if c.isClosed() {
+		return 0, wrapError(errClosed)
+	}
+
+	n, err := readSocket(c.fd, b, 0)
 	if err != nil {
-		return TCPConn{}, err
+		c.logError("could not read from connection", err)
+	}
+
+	return n, err
+}
+
+func (c TCPConn) Write(b []byte) (int, error) {
+	if c.isClosed() {
+		return 0, wrapError(errClosed)
 	}
 
-	conn, err := DialTCP(network, nil, raddr) // TODO: Set laddr here
+	n, err := writeSocket(c.fd, b, 0)
 	if err != nil {
-		return TCPConn{}, err
+		c.logError("could not write to connection", err)
 	}
 
-	return *conn, err
+	return n, err
 }
 
-func DialTCP(network string, laddr, raddr *TCPAddr) (*TCPConn, error) {
-	// Create address
-	serverAddress := unisockets.SockaddrIn{
-		SinFamily: unisockets.PF_INET,
-		SinPort:   unisockets.Htons(uint16(raddr.Port)),
-		SinAddr: struct{ SAddr uint32 }{
-			SAddr: binary.LittleEndian.Uint32(raddr.IP),
-		},
+// Overrides the package's logger; has to be set before the connection is used
+func (c TCPConn) SetLogger(logger Logger) {
+	if c.state != nil {
+		c.state.logger = logger
+	}
+}
+
+func (c TCPConn) log() Logger {
+	if c.state != nil && c.state.logger != nil {
+		return c.state.logger
+	}
+
+	return getLogger()
+}
+
+func (c TCPConn) logError(msg string, err error) {
+	switch {
+	case errors.Is(err, errDisconnected):
+		c.log().Info("peer disconnected", "raddr", c.RemoteAddr())
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		// Deadlines are set deliberately
+	default:
+		c.log().Error(msg, "raddr", c.RemoteAddr(), "err", err)
 	}
+}
+
+func (c TCPConn) TryRead(b []byte) (int, error) {
+	if c.isClosed() {
+		return 0, wrapError(errClosed)
+	}
+
+	n, err := readSocket(c.fd, b, msgDontWait)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return 0, &Error{Err: errWouldBlock, IsTimeout: false, IsTemporary: true}
+	}
+
+	return n, err
+}
+
+func (c TCPConn) TryWrite(b []byte) (int, error) {
+	if c.isClosed() {
+		return 0, wrapError(errClosed)
+	}
+
+	n, err := writeSocket(c.fd, b, msgDontWait)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return 0, &Error{Err: errWouldBlock, IsTimeout: false, IsTemporary: true}
+	}
+
+	return n, err
+}
+
+func (c TCPConn) Peek(n int) ([]byte, error) {
+	if c.isClosed() {
+		return nil, wrapError(errClosed)
+	}
+
+	if n <= 0 {
+		return []byte{}, nil
+	}
+
+	// Block until n bytes are available, but leave them in the receive buffer
+	b := make([]byte, n)
+	m, err := readSocket(c.fd, b, msgPeek|msgWaitAll)
+
+	return b[:m], err
+}
+
+func (c TCPConn) Writev(buffers [][]byte) (int64, error) {
+	if c.isClosed() {
+		return 0, wrapError(errClosed)
+	}
+
+	// Don't modify the caller's slice when advancing over partial writes
+	buffers = append([][]byte{}, buffers...)
+
+	written := int64(0)
+	for len(buffers) > 0 {
+		n, err := writev(c.fd, buffers)
+		written += n
+		if err != nil {
+			if isTimeout(err) {
+				return written, wrapError(os.ErrDeadlineExceeded)
+			}
+
+			return written, wrapError(err)
+		}
 
-	// Create socket
-	serverSocket, err := unisockets.Socket(unisockets.PF_INET, unisockets.SOCK_STREAM, 0)
+		// Drop the buffers which were written completely and retry the rest
+		for len(buffers) > 0 && n >= int64(len(buffers[0])) {
+			n -= int64(len(buffers[0]))
+			buffers = buffers[1:]
+		}
+
+		if len(buffers) > 0 {
+			buffers[0] = buffers[0][n:]
+		}
+	}
+
+	return written, nil
+}
+
+func (c TCPConn) ReadBuffers(buffers [][]byte) (int64, error) {
+	if c.isClosed() {
+		return 0, wrapError(errClosed)
+	}
+
+	empty := true
+	for _, b := range buffers {
+		if len(b) > 0 {
+			empty = false
+
+			break
+		}
+	}
+
+	if empty {
+		return 0, nil
+	}
+
+	n, err := readv(c.fd, buffers)
 	if err != nil {
-		return nil, err
+		if isTimeout(err) {
+			return 0, wrapError(os.ErrDeadlineExceeded)
+		}
+
+		return 0, wrapError(err)
 	}
 
-	// Connect
-	if err := unisockets.Connect(serverSocket, &serverAddress); err != nil {
-		return nil, err
+	if n == 0 {
+		return 0, errDisconnected
 	}
 
-	return &TCPConn{
-		fd:    serverSocket,
-		laddr: laddr,
-		raddr: raddr,
-	}, nil
+	return n, nil
 }
 
-type TCPConn struct {
-	fd int32
+func (c TCPConn) RecvMsg(b []byte, oob []byte, flags int) (int, int, int, net.Addr, error) {
+	if c.isClosed() {
+		return 0, 0, 0, nil, wrapError(errClosed)
+	}
 
-	laddr net.Addr
-	raddr net.Addr
+	n, oobn, recvflags, ip, port, err := recvMsg(c.fd, b, oob, flags)
+	if err != nil {
+		if isTimeout(err) {
+			return 0, 0, 0, nil, wrapError(os.ErrDeadlineExceeded)
+		}
+
+		return 0, 0, 0, nil, wrapError(err)
+	}
+
+	// Connected sockets usually don't report the source address
+	addr := c.raddr
+	if ip != nil {
+		addr = &TCPAddr{
+			stringAddr: "",
+			IP:         ip,
+			Port:       port,
+			Zone:       "",
+		}
+	}
+
+	return n, oobn, recvflags, addr, nil
 }
 
-func (c TCPConn) Read(b []byte) (int, error) {
+func (c TCPConn) SendMsg(b []byte, oob []byte, flags int) (int, int, error) {
+	if c.isClosed() {
+		return 0, 0, wrapError(errClosed)
+	}
+
+	n, err := sendMsg(c.fd, b, oob, flags)
+	if err != nil {
+		if isTimeout(err) {
+			return 0, 0, wrapError(os.ErrDeadlineExceeded)
+		}
+
+		return 0, 0, wrapError(err)
+	}
+
+	return n, len(oob), nil
+}
+
+func (c TCPConn) SendOOB(b []byte) (int, error) {
+	if c.isClosed() {
+		return 0, wrapError(errClosed)
+	}
+
+	return writeSocket(c.fd, b, msgOOB)
+}
+
+func (c TCPConn) RecvOOB(b []byte) (int, error) {
+	if c.isClosed() {
+		return 0, wrapError(errClosed)
+	}
+
+	return readSocket(c.fd, b, msgOOB)
+}
+
+func (c TCPConn) ReadFrom(r io.Reader) (int64, error) {
+	if c.isClosed() {
+		return 0, wrapError(errClosed)
+	}
+
+	if f, ok := r.(*os.File); ok {
+		if n, handled, err := sendFile(c.fd, f); handled {
+			return n, wrapError(err)
+		}
+	}
+
+	// Hide ReadFrom so that io.Copy doesn't recurse
+	return io.Copy(writerOnly{c}, r)
+}
+
+type writerOnly struct {
+	io.Writer
+}
+
+func (c TCPConn) WriteTo(w io.Writer) (int64, error) {
+	if c.isClosed() {
+		return 0, wrapError(errClosed)
+	}
+
+	// Disconnects end the copy like io.EOF; eofReader also hides WriteTo so that io.Copy doesn't recurse
+	return io.Copy(w, eofReader{c})
+}
+
+func (c TCPConn) WriteBuffers(v *net.Buffers) (int64, error) {
+	n, err := c.Writev(*v)
+
+	// Consume the written bytes like net.Buffers.WriteTo does
+	remaining := n
+	for len(*v) > 0 && remaining >= int64(len((*v)[0])) {
+		remaining -= int64(len((*v)[0]))
+		*v = (*v)[1:]
+	}
+
+	if len(*v) > 0 {
+		(*v)[0] = (*v)[0][remaining:]
+	}
+
+	return n, err
+}
+
+func readSocket(fd int32, b []byte, flags int32) (int, error) {
 	readMsg := make([]byte, len(b))
 
-	n, err := unisockets.Recv(c.fd, &readMsg, uint32(len(b)), 0)
+	n, err := unisockets.Recv(fd, &readMsg, uint32(len(b)), flags)
+	if err != nil && isTimeout(err) {
+		return 0, wrapError(os.ErrDeadlineExceeded)
+	}
+
+	if n < 0 {
+		return 0, wrapError(err)
+	}
+
 	if n == 0 {
-		return int(n), errors.New("client disconnected")
+		return int(n), errDisconnected
 	}
 
 	copy(b, readMsg)
 
-	return int(n), err
+	return int(n), wrapError(err)
 }
 
-func (c TCPConn) Write(b []byte) (int, error) {
-	n, err := unisockets.Send(c.fd, b, 0)
+func writeSocket(fd int32, b []byte, flags int32) (int, error) {
+	n, err := unisockets.Send(fd, b, flags)
+	if err != nil && isTimeout(err) {
+		return 0, wrapError(os.ErrDeadlineExceeded)
+	}
+
+	if n < 0 {
+		return 0, wrapError(err)
+	}
+
 	if n == 0 {
-		return int(n), errors.New("client disconnected")
+		return int(n), errDisconnected
 	}
 
-	return int(n), err
+	return int(n), wrapError(err)
 }
 
 func (c TCPConn) Close() error {
-	return unisockets.Shutdown(c.fd, unisockets.SHUT_RDWR)
+	if c.state == nil {
+		return wrapError(unisockets.Shutdown(c.fd, unisockets.SHUT_RDWR))
+	}
+
+	// Subsequent closes are no-ops
+	var err error
+	c.state.closeOnce.Do(func() {
+		atomic.StoreInt32(&c.state.closed, 1)
+
+		c.log().Debug("closed connection", "raddr", c.RemoteAddr())
+
+		err = shutdownAndClose(c.fd)
+
+		if c.state.onClose != nil {
+			c.state.onClose()
+		}
+	})
+
+	return err
+}
+
+// Shutting down first wakes up reads and writes which are blocked on the socket in other goroutines
+func shutdownAndClose(fd int32) error {
+	err := unisockets.Shutdown(fd, unisockets.SHUT_RDWR)
+
+	if closeErr := closeSocket(fd); err == nil {
+		err = closeErr
+	}
+
+	return wrapError(err)
+}
+
+func (c TCPConn) CloseRead() error {
+	if c.isClosed() {
+		return wrapError(errClosed)
+	}
+
+	return wrapError(unisockets.Shutdown(c.fd, shutRd))
+}
+
+func (c TCPConn) CloseWrite() error {
+	if c.isClosed() {
+		return wrapError(errClosed)
+	}
+
+	return wrapError(unisockets.Shutdown(c.fd, shutWr))
+}
+
+func (c TCPConn) SetKeepAlive(keepAlive bool) error {
+	return setKeepAlive(c.fd, keepAlive)
+}
+
+func (c TCPConn) SetKeepAlivePeriod(d time.Duration) error {
+	return setKeepAlivePeriod(c.fd, d)
+}
+
+func (c TCPConn) SetKeepAliveConfig(cfg KeepAliveConfig) error {
+	if err := setKeepAlive(c.fd, cfg.Enable); err != nil {
+		return err
+	}
+
+	if !cfg.Enable {
+		return nil
+	}
+
+	idle := cfg.Idle
+	if idle == 0 {
+		idle = DefaultKeepAlivePeriod
+	}
+
+	if idle > 0 {
+		if err := setKeepAliveIdle(c.fd, idle); err != nil {
+			return err
+		}
+	}
+
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = DefaultKeepAlivePeriod
+	}
+
+	if interval > 0 {
+		if err := setKeepAliveInterval(c.fd, interval); err != nil {
+			return err
+		}
+	}
+
+	count := cfg.Count
+	if count == 0 {
+		count = DefaultKeepAliveCount
+	}
+
+	if count > 0 {
+		if err := setKeepAliveCount(c.fd, count); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c TCPConn) BindToDevice(ifname string) error {
+	return bindToDevice(c.fd, ifname)
+}
+
+func (c TCPConn) SetLinger(sec int) error {
+	return setLinger(c.fd, sec)
+}
+
+func (c TCPConn) SetNoDelay(noDelay bool) error {
+	return setNoDelay(c.fd, noDelay)
+}
+
+// The kernel may switch back to delayed ACKs after any read, so this only
+// lasts until the next one; use QuickAckConn to re-enable it automatically.
+func (c TCPConn) SetQuickAck(quickAck bool) error {
+	return setQuickAck(c.fd, quickAck)
+}
+
+// Setting a mark requires CAP_NET_ADMIN
+func (c TCPConn) SetMark(mark uint32) error {
+	return setMark(c.fd, mark)
+}
+
+// Algorithms other than the default ones may need to be allowed in net.ipv4.tcp_allowed_congestion_control
+func (c TCPConn) SetCongestionControl(algorithm string) error {
+	return setCongestionControl(c.fd, algorithm)
+}
+
+func (c TCPConn) GetCongestionControl() (string, error) {
+	return getCongestionControl(c.fd)
+}
+
+func (c TCPConn) SetNonblock(nonblocking bool) error {
+	return setNonblock(c.fd, nonblocking)
+}
+
+func (c TCPConn) SetOOBInline(inline bool) error {
+	return setOOBInline(c.fd, inline)
+}
+
+func (c TCPConn) SetSendBufferSize(bytes int) error {
+	return setSendBuffer(c.fd, bytes)
+}
+
+func (c TCPConn) SetReceiveBufferSize(bytes int) error {
+	return setReceiveBuffer(c.fd, bytes)
+}
+
+func (c TCPConn) GetSendBufferSize() (int, error) {
+	return getSendBuffer(c.fd)
+}
+
+func (c TCPConn) GetReceiveBufferSize() (int, error) {
+	return getReceiveBuffer(c.fd)
+}
+
+func (c TCPConn) OriginalDestination() (*TCPAddr, error) {
+	ip, port, err := originalDestination(c.fd, c.isIPv6())
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return &TCPAddr{
+		stringAddr: "",
+		IP:         ip,
+		Port:       port,
+		Zone:       "",
+	}, nil
 }
 
 func (c TCPConn) LocalAddr() net.Addr {
-	return c.laddr
+	if c.state == nil || !isEmptyTCPAddr(c.laddr) {
+		return c.laddr
+	}
+
+	c.state.laddrOnce.Do(func() {
+		c.state.laddr = lookupTCPAddr(c.laddr, c.fd, getsockname)
+	})
+
+	return c.state.laddr
 }
 
 func (c TCPConn) RemoteAddr() net.Addr {
-	return c.laddr
+	if c.state == nil || !isEmptyTCPAddr(c.raddr) {
+		return c.raddr
+	}
+
+	c.state.raddrOnce.Do(func() {
+		c.state.raddr = lookupTCPAddr(c.raddr, c.fd, getpeername)
+	})
+
+	return c.state.raddr
+}
+
+func isEmptyTCPAddr(addr net.Addr) bool {
+	if addr == nil {
+		return true
+	}
+
+	tcpAddr, ok := addr.(*TCPAddr)
+	if !ok {
+		return false
+	}
+
+	return tcpAddr == nil || len(tcpAddr.IP) == 0 || net.IP(tcpAddr.IP).IsUnspecified() || tcpAddr.Port == 0
+}
+
+func lookupTCPAddr(fallback net.Addr, fd int32, lookup func(fd int32) (IP, int, string, error)) net.Addr {
+	ip, port, zone, err := lookup(fd)
+	if err != nil {
+		return fallback
+	}
+
+	return &TCPAddr{
+		stringAddr: "",
+		IP:         ip,
+		Port:       port,
+		Zone:       zone,
+	}
 }
 
 func (c TCPConn) SetDeadline(t time.Time) error {
-	// TODO: Currently there is an infinite deadline
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
 
-	return nil
+	return c.SetWriteDeadline(t)
 }
 
 func (c TCPConn) SetReadDeadline(t time.Time) error {
-	// TODO: Currently there is an infinite deadline
-
-	return nil
+	return setReadTimeout(c.fd, t)
 }
 
 func (c TCPConn) SetWriteDeadline(t time.Time) error {
-	// TODO: Currently there is an infinite deadline
-
-	return nil
+	return setWriteTimeout(c.fd, t)
 }
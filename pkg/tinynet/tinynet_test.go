@@ -0,0 +1,48 @@
+package tinynet
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestRemoteAddr(t *testing.T) {
+	client, server := dialPeer(t)
+
+	if actual, expected := client.RemoteAddr().String(), server.LocalAddr().String(); actual != expected {
+		t.Fatalf("client reported remote address %v, expected %v", actual, expected)
+	}
+
+	if actual, expected := server.RemoteAddr().String(), client.LocalAddr().String(); actual != expected {
+		t.Fatalf("server reported remote address %v, expected %v", actual, expected)
+	}
+
+	if port := server.RemoteAddr().(*TCPAddr).Port; port == 0 {
+		t.Fatal("server reported remote port 0")
+	}
+}
+
+func TestCongestionControl(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("congestion control can only be selected on Linux")
+	}
+
+	conn, _ := dialPeer(t)
+
+	// Reno is built into the kernel and always allowed
+	if err := conn.SetCongestionControl("reno"); err != nil {
+		t.Fatal(err)
+	}
+
+	algorithm, err := conn.GetCongestionControl()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if algorithm != "reno" {
+		t.Fatalf("got congestion control %v, expected reno", algorithm)
+	}
+
+	if err := conn.SetCongestionControl("unknown"); err == nil {
+		t.Fatal("set unknown congestion control")
+	}
+}
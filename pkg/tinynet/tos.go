@@ -0,0 +1,46 @@
+package tinynet
+
+// DSCP code points, already shifted into the upper six bits of the TOS byte
+const (
+	DSCPCS0  = 0x00
+	DSCPCS1  = 0x20
+	DSCPAF11 = 0x28
+	DSCPAF12 = 0x30
+	DSCPAF13 = 0x38
+	DSCPCS2  = 0x40
+	DSCPAF21 = 0x48
+	DSCPAF22 = 0x50
+	DSCPAF23 = 0x58
+	DSCPCS3  = 0x60
+	DSCPAF31 = 0x68
+	DSCPAF32 = 0x70
+	DSCPAF33 = 0x78
+	DSCPCS4  = 0x80
+	DSCPAF41 = 0x88
+	DSCPAF42 = 0x90
+	DSCPAF43 = 0x98
+	DSCPCS5  = 0xa0
+	DSCPEF   = 0xb8
+	DSCPCS6  = 0xc0
+	DSCPCS7  = 0xe0
+)
+
+func (c TCPConn) isIPv6() bool {
+	if laddr, ok := c.laddr.(*TCPAddr); ok && laddr != nil {
+		return isIPv6(laddr.IP)
+	}
+
+	if raddr, ok := c.raddr.(*TCPAddr); ok && raddr != nil {
+		return isIPv6(raddr.IP)
+	}
+
+	return false
+}
+
+func (c TCPConn) SetTOS(tos int) error {
+	return setTOS(c.fd, tos, c.isIPv6())
+}
+
+func (c *UDPConn) SetTOS(tos int) error {
+	return setTOS(c.fd, tos, c.isIPv6())
+}
@@ -0,0 +1,137 @@
+package tinynet
+
+import (
+	"context"
+	"net"
+	"sync"
+)
+
+type ConnectionTracker struct {
+	conns map[*trackedConn]struct{}
+	idle  chan struct{}
+	lock  sync.Mutex
+}
+
+func NewConnectionTracker() *ConnectionTracker {
+	return &ConnectionTracker{
+		conns: map[*trackedConn]struct{}{},
+	}
+}
+
+func (t *ConnectionTracker) Wrap(inner net.Conn) net.Conn {
+	conn := &trackedConn{
+		Conn:    inner,
+		tracker: t,
+	}
+
+	t.add(conn)
+
+	return conn
+}
+
+// Tracks a connection without wrapping it, so that callers still get a *TCPConn
+func (t *ConnectionTracker) trackTCP(inner *TCPConn) {
+	conn := &trackedConn{
+		Conn:    inner,
+		tracker: t,
+	}
+
+	t.add(conn)
+
+	inner.state.onClose = conn.untrack
+}
+
+func (t *ConnectionTracker) Len() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return len(t.conns)
+}
+
+func (t *ConnectionTracker) WaitIdle(ctx context.Context) error {
+	t.lock.Lock()
+	if len(t.conns) == 0 {
+		t.lock.Unlock()
+
+		return nil
+	}
+
+	// Closed by the last connection to be removed
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	idle := t.idle
+	t.lock.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-idle:
+		return nil
+	}
+}
+
+func (t *ConnectionTracker) CloseAll() error {
+	t.lock.Lock()
+	conns := make([]*trackedConn, 0, len(t.conns))
+	for conn := range t.conns {
+		conns = append(conns, conn)
+	}
+	t.lock.Unlock()
+
+	var firstErr error
+	for _, conn := range conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func (t *ConnectionTracker) add(conn *trackedConn) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.conns[conn] = struct{}{}
+}
+
+func (t *ConnectionTracker) remove(conn *trackedConn) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.conns, conn)
+
+	if len(t.conns) == 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+}
+
+type trackedConn struct {
+	net.Conn
+
+	tracker    *ConnectionTracker
+	removeOnce sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	err := c.Conn.Close()
+
+	c.untrack()
+
+	return err
+}
+
+func (c *trackedConn) untrack() {
+	c.removeOnce.Do(func() {
+		c.tracker.remove(c)
+	})
+}
+
+// Stops a connection whose socket has been handed over elsewhere from holding up Shutdown
+func (c TCPConn) untrack() {
+	if c.state != nil && c.state.onClose != nil {
+		c.state.onClose()
+	}
+}
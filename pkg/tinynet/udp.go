@@ -0,0 +1,245 @@
+package tinynet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/alphahorizonio/unisockets/pkg/unisockets"
+)
+
+type UDPAddr struct {
+	stringAddr string
+
+	IP   IP
+	Port int
+	Zone string
+}
+
+func (u *UDPAddr) Network() string {
+	return "udp"
+}
+
+func (u *UDPAddr) String() string {
+	if u.stringAddr == "" {
+		return formatAddr(u.IP, u.Port, u.Zone)
+	}
+
+	return u.stringAddr
+}
+
+func ResolveUDPAddr(network, address string) (*UDPAddr, error) {
+	return DefaultResolver.ResolveUDPAddr(context.Background(), network, address)
+}
+
+func ListenUDP(network string, laddr *UDPAddr) (*UDPConn, error) {
+	if err := checkUDPNetwork(network); err != nil {
+		return nil, err
+	}
+
+	ip, err := ipForNetwork(network, laddr.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ip.Equal(laddr.IP) {
+		laddr = &UDPAddr{
+			stringAddr: laddr.stringAddr,
+			IP:         ip,
+			Port:       laddr.Port,
+			Zone:       laddr.Zone,
+		}
+	}
+
+	// Create socket
+	serverSocket, err := newSocket(laddr.IP, sockDgram)
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't leak the socket if any of the following steps fail
+	bound := false
+	defer func() {
+		if !bound {
+			_ = closeSocket(serverSocket)
+		}
+	}()
+
+	// Bind
+	if err := bindSocket(serverSocket, laddr.IP, laddr.Port, laddr.Zone); err != nil {
+		return nil, err
+	}
+
+	bound = true
+
+	return &UDPConn{
+		fd:    serverSocket,
+		laddr: laddr,
+	}, nil
+}
+
+func DialUDP(network string, laddr, raddr *UDPAddr) (*UDPConn, error) {
+	if err := checkUDPNetwork(network); err != nil {
+		return nil, err
+	}
+
+	if _, err := ipForNetwork(network, raddr.IP); err != nil {
+		return nil, err
+	}
+
+	// Create socket
+	serverSocket, err := newSocket(raddr.IP, sockDgram)
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't leak the socket if any of the following steps fail
+	dialed := false
+	defer func() {
+		if !dialed {
+			_ = closeSocket(serverSocket)
+		}
+	}()
+
+	// Bind
+	if laddr != nil {
+		if err := bindSocket(serverSocket, laddr.IP, laddr.Port, laddr.Zone); err != nil {
+			return nil, err
+		}
+	}
+
+	// Connect
+	if err := connectSocket(serverSocket, raddr.IP, raddr.Port, raddr.Zone); err != nil {
+		return nil, err
+	}
+
+	conn := &UDPConn{
+		fd:    serverSocket,
+		raddr: raddr,
+	}
+
+	if laddr != nil {
+		conn.laddr = laddr
+	}
+
+	dialed = true
+
+	return conn, nil
+}
+
+type UDPConn struct {
+	fd        int32
+	closeOnce sync.Once
+
+	laddr net.Addr
+	raddr net.Addr
+}
+
+func (c *UDPConn) Read(b []byte) (int, error) {
+	readMsg := make([]byte, len(b))
+
+	n, err := unisockets.Recv(c.fd, &readMsg, uint32(len(b)), 0)
+	if err != nil {
+		if isTimeout(err) {
+			return 0, os.ErrDeadlineExceeded
+		}
+
+		return 0, err
+	}
+
+	copy(b, readMsg)
+
+	return int(n), nil
+}
+
+func (c *UDPConn) Write(b []byte) (int, error) {
+	if c.raddr == nil {
+		return 0, errors.New("could not write to unconnected socket")
+	}
+
+	n, err := unisockets.Send(c.fd, b, 0)
+	if err != nil {
+		if isTimeout(err) {
+			return 0, os.ErrDeadlineExceeded
+		}
+
+		return 0, err
+	}
+
+	return int(n), nil
+}
+
+func (c *UDPConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	n, ip, port, err := recvFrom(c.fd, b)
+	if err != nil {
+		if isTimeout(err) {
+			return 0, nil, os.ErrDeadlineExceeded
+		}
+
+		return 0, nil, err
+	}
+
+	return n, &UDPAddr{
+		stringAddr: "",
+		IP:         ip,
+		Port:       port,
+		Zone:       "",
+	}, nil
+}
+
+func (c *UDPConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	raddr, ok := addr.(*UDPAddr)
+	if !ok {
+		return 0, errors.New("could not use non-UDP address")
+	}
+
+	if err := sendTo(c.fd, b, raddr.IP, raddr.Port, raddr.Zone); err != nil {
+		if isTimeout(err) {
+			return 0, os.ErrDeadlineExceeded
+		}
+
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// Subsequent closes are no-ops, as the fd might belong to another socket by now
+func (c *UDPConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		// Wakes up blocked reads; unconnected sockets report ENOTCONN, but are shut down nonetheless
+		_ = unisockets.Shutdown(c.fd, unisockets.SHUT_RDWR)
+
+		err = closeSocket(c.fd)
+	})
+
+	return err
+}
+
+func (c *UDPConn) LocalAddr() net.Addr {
+	return c.laddr
+}
+
+func (c *UDPConn) RemoteAddr() net.Addr {
+	return c.raddr
+}
+
+func (c *UDPConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+
+	return c.SetWriteDeadline(t)
+}
+
+func (c *UDPConn) SetReadDeadline(t time.Time) error {
+	return setReadTimeout(c.fd, t)
+}
+
+func (c *UDPConn) SetWriteDeadline(t time.Time) error {
+	return setWriteTimeout(c.fd, t)
+}
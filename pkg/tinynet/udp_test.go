@@ -0,0 +1,38 @@
+package tinynet
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestUDPConnClose(t *testing.T) {
+	conn, err := ListenUDP("udp4", &UDPAddr{IP: IP(net.IPv4(127, 0, 0, 1)), Port: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read := make(chan error, 1)
+	go func() {
+		_, _, err := conn.ReadFrom(make([]byte, 1))
+
+		read <- err
+	}()
+
+	// Give ReadFrom time to block
+	time.Sleep(100 * time.Millisecond)
+
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-read:
+	case <-time.After(2 * time.Second):
+		t.Fatal("ReadFrom was not unblocked by Close")
+	}
+
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
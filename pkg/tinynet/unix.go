@@ -0,0 +1,325 @@
+package tinynet
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/alphahorizonio/unisockets/pkg/unisockets"
+)
+
+type UnixAddr struct {
+	Name string
+	Net  string
+}
+
+func (u *UnixAddr) Network() string {
+	return u.Net
+}
+
+func (u *UnixAddr) String() string {
+	return u.Name
+}
+
+func ResolveUnixAddr(network, address string) (*UnixAddr, error) {
+	return &UnixAddr{
+		Name: address,
+		Net:  network,
+	}, nil
+}
+
+// Names starting with '@' or a null byte are in Linux's abstract namespace;
+// package syscall substitutes the leading '@' with a null byte.
+func isAbstractUnix(name string) bool {
+	return len(name) > 0 && (name[0] == '@' || name[0] == 0)
+}
+
+func unixSocketType(network string) (int32, error) {
+	switch network {
+	case "unix":
+		return unisockets.SOCK_STREAM, nil
+	case "unixgram":
+		return sockDgram, nil
+	default:
+		return -1, fmt.Errorf("unsupported network %v", network)
+	}
+}
+
+func ListenUnixgram(network, laddr string) (*UnixConn, error) {
+	if network != "unixgram" {
+		return nil, fmt.Errorf("unsupported network %v", network)
+	}
+
+	// Create socket
+	serverSocket, err := socketUnix(sockDgram)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bind
+	if err := bindUnix(serverSocket, laddr); err != nil {
+		_ = closeSocket(serverSocket)
+
+		return nil, err
+	}
+
+	return &UnixConn{
+		fd: serverSocket,
+		laddr: &UnixAddr{
+			Name: laddr,
+			Net:  network,
+		},
+	}, nil
+}
+
+func ListenUnix(network string, laddr *UnixAddr) (*UnixListener, error) {
+	return NewListenConfig().listenUnix(network, laddr)
+}
+
+func (lc *ListenConfig) listenUnix(network string, laddr *UnixAddr) (*UnixListener, error) {
+	backlog := lc.Backlog
+	if backlog <= 0 {
+		backlog = DefaultBacklog
+	}
+
+	// Create socket
+	serverSocket, err := socketUnix(unisockets.SOCK_STREAM)
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't leak the socket if any of the following steps fail
+	listening := false
+	defer func() {
+		if !listening {
+			_ = closeSocket(serverSocket)
+		}
+	}()
+
+	// Bind
+	if err := bindUnix(serverSocket, laddr.Name); err != nil {
+		return nil, err
+	}
+
+	// Listen
+	if err := unisockets.Listen(serverSocket, int32(backlog)); err != nil {
+		return nil, err
+	}
+
+	listening = true
+
+	return &UnixListener{
+		fd:   serverSocket,
+		addr: laddr,
+	}, nil
+}
+
+type UnixListener struct {
+	fd     int32
+	addr   *UnixAddr
+	closed int32
+}
+
+func (l *UnixListener) Close() error {
+	// Subsequent closes are no-ops; the fd and the socket file might belong to a new listener by now
+	if !atomic.CompareAndSwapInt32(&l.closed, 0, 1) {
+		return nil
+	}
+
+	if err := shutdownAndClose(l.fd); err != nil {
+		return err
+	}
+
+	// Abstract sockets don't have a file to remove
+	if isAbstractUnix(l.addr.Name) {
+		return nil
+	}
+
+	// Remove the socket file
+	if err := os.Remove(l.addr.Name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (l *UnixListener) Addr() net.Addr {
+	return l.addr
+}
+
+func (l *UnixListener) Accept() (net.Conn, error) {
+	return l.AcceptUnix()
+}
+
+func (l *UnixListener) AcceptUnix() (*UnixConn, error) {
+	// The fd might already belong to another socket
+	if atomic.LoadInt32(&l.closed) == 1 {
+		return nil, wrapError(errClosed)
+	}
+
+	// Accept
+	clientSocket, name, err := acceptUnix(l.fd)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UnixConn{
+		fd:    clientSocket,
+		laddr: l.addr,
+		raddr: &UnixAddr{
+			Name: name,
+			Net:  l.addr.Net,
+		},
+	}, nil
+}
+
+func DialUnix(network string, laddr, raddr *UnixAddr) (*UnixConn, error) {
+	sotype, err := unixSocketType(network)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create socket
+	clientSocket, err := socketUnix(sotype)
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't leak the socket if any of the following steps fail
+	dialed := false
+	defer func() {
+		if !dialed {
+			_ = closeSocket(clientSocket)
+		}
+	}()
+
+	// Bind
+	if laddr != nil {
+		if err := bindUnix(clientSocket, laddr.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	// Connect
+	if err := connectUnix(clientSocket, raddr.Name); err != nil {
+		return nil, err
+	}
+
+	conn := &UnixConn{
+		fd:    clientSocket,
+		raddr: raddr,
+	}
+
+	if laddr != nil {
+		conn.laddr = laddr
+	}
+
+	dialed = true
+
+	return conn, nil
+}
+
+type UnixConn struct {
+	fd     int32
+	closed int32
+
+	laddr net.Addr
+	raddr net.Addr
+}
+
+// Mirrors syscall.Ucred, which isn't available on every platform
+type Ucred struct {
+	Pid int32
+	Uid uint32
+	Gid uint32
+}
+
+func (c *UnixConn) Read(b []byte) (int, error) {
+	return readSocket(c.fd, b, 0)
+}
+
+func (c *UnixConn) Write(b []byte) (int, error) {
+	return writeSocket(c.fd, b, 0)
+}
+
+func (c *UnixConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	n, name, err := recvFromUnix(c.fd, b)
+	if err != nil {
+		if isTimeout(err) {
+			return 0, nil, os.ErrDeadlineExceeded
+		}
+
+		return 0, nil, err
+	}
+
+	return n, &UnixAddr{
+		Name: name,
+		Net:  "unixgram",
+	}, nil
+}
+
+func (c *UnixConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	unixAddr, ok := addr.(*UnixAddr)
+	if !ok {
+		return 0, errors.New("could not use non-Unix address")
+	}
+
+	if err := sendToUnix(c.fd, b, unixAddr.Name); err != nil {
+		if isTimeout(err) {
+			return 0, os.ErrDeadlineExceeded
+		}
+
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+func (c *UnixConn) Close() error {
+	// Subsequent closes are no-ops
+	if !atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		return nil
+	}
+
+	return shutdownAndClose(c.fd)
+}
+
+func (c *UnixConn) CloseRead() error {
+	return unisockets.Shutdown(c.fd, shutRd)
+}
+
+func (c *UnixConn) CloseWrite() error {
+	return unisockets.Shutdown(c.fd, shutWr)
+}
+
+func (c *UnixConn) LocalAddr() net.Addr {
+	return c.laddr
+}
+
+func (c *UnixConn) RemoteAddr() net.Addr {
+	return c.raddr
+}
+
+// The credentials are those of the peer process at the time it connected
+func (c *UnixConn) PeerCredentials() (*Ucred, error) {
+	return peerCredentials(c.fd)
+}
+
+func (c *UnixConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+
+	return c.SetWriteDeadline(t)
+}
+
+func (c *UnixConn) SetReadDeadline(t time.Time) error {
+	return setReadTimeout(c.fd, t)
+}
+
+func (c *UnixConn) SetWriteDeadline(t time.Time) error {
+	return setWriteTimeout(c.fd, t)
+}
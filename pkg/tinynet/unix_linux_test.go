@@ -0,0 +1,73 @@
+//go:build linux && !js && !tinygo
+// +build linux,!js,!tinygo
+
+package tinynet
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAbstractUnix(t *testing.T) {
+	name := fmt.Sprintf("@tinynet-test-%v-%v", os.Getpid(), time.Now().UnixNano())
+
+	l, err := ListenUnix("unix", &UnixAddr{Name: name, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// Abstract sockets must not create a file
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Fatalf("got error %v for %v, expected it not to exist", err, name)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			errs <- err
+
+			return
+		}
+		defer conn.Close()
+
+		buf := make([]byte, 5)
+		if _, err := ReadFull(conn, buf); err != nil {
+			errs <- err
+
+			return
+		}
+
+		_, err = conn.Write(buf)
+
+		errs <- err
+	}()
+
+	conn, err := DialUnix("unix", nil, &UnixAddr{Name: name, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	expected := []byte("hello")
+	if _, err := conn.Write(expected); err != nil {
+		t.Fatal(err)
+	}
+
+	actual := make([]byte, len(expected))
+	if _, err := ReadFull(conn, actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, expected) {
+		t.Fatalf("read %q, expected %q", actual, expected)
+	}
+
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,68 @@
+package tinynet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUnixListenerClose(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "tinynet.sock")
+
+	l, err := ListenUnix("unix", &UnixAddr{Name: name, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accepted := make(chan error, 1)
+	go func() {
+		_, err := l.Accept()
+
+		accepted <- err
+	}()
+
+	// Give Accept time to block
+	time.Sleep(100 * time.Millisecond)
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-accepted:
+		if err == nil {
+			t.Fatal("accepted connection from closed listener")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Accept was not unblocked by Close")
+	}
+
+	// A second close must not touch the socket file of a new listener
+	next, err := ListenUnix("unix", &UnixAddr{Name: name, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer next.Close()
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(name); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := DialUnix("unix", nil, &UnixAddr{Name: name, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
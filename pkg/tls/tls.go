@@ -0,0 +1,33 @@
+package tls
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+)
+
+type Role int
+
+const (
+	RoleClient Role = iota
+	RoleServer
+)
+
+func UpgradeTLS(conn net.Conn, cfg *tls.Config, role Role) (*tls.Conn, error) {
+	var tlsConn *tls.Conn
+
+	switch role {
+	case RoleClient:
+		tlsConn = tls.Client(conn, cfg)
+	case RoleServer:
+		tlsConn = tls.Server(conn, cfg)
+	default:
+		return nil, errors.New("could not upgrade connection with unknown role")
+	}
+
+	if err := tlsConn.Handshake(); err != nil {
+		return nil, err
+	}
+
+	return tlsConn, nil
+}
@@ -0,0 +1,138 @@
+package tls_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+	tinytls "github.com/alphahorizonio/tinynet/pkg/tls"
+)
+
+func selfSignedCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, pool
+}
+
+func TestUpgradeTLS(t *testing.T) {
+	cert, pool := selfSignedCertificate(t)
+
+	l, err := tinynet.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	errs := make(chan error, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			errs <- err
+
+			return
+		}
+		defer conn.Close()
+
+		tlsConn, err := tinytls.UpgradeTLS(conn, &tls.Config{Certificates: []tls.Certificate{cert}}, tinytls.RoleServer)
+		if err != nil {
+			errs <- err
+
+			return
+		}
+
+		// Echo a single message
+		buf := make([]byte, 5)
+		if _, err := io.ReadFull(tlsConn, buf); err != nil {
+			errs <- err
+
+			return
+		}
+
+		_, err = tlsConn.Write(buf)
+
+		errs <- err
+	}()
+
+	conn, err := tinynet.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	tlsConn, err := tinytls.UpgradeTLS(conn, &tls.Config{RootCAs: pool, ServerName: "127.0.0.1"}, tinytls.RoleClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "hello"
+	if _, err := tlsConn.Write([]byte(expected)); err != nil {
+		t.Fatal(err)
+	}
+
+	actual := make([]byte, len(expected))
+	if _, err := io.ReadFull(tlsConn, actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(actual) != expected {
+		t.Fatalf("read %q, expected %q", actual, expected)
+	}
+
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpgradeTLSUnknownRole(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	if _, err := tinytls.UpgradeTLS(client, &tls.Config{}, tinytls.Role(-1)); err == nil {
+		t.Fatal("upgraded connection with unknown role")
+	}
+}
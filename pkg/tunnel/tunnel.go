@@ -0,0 +1,147 @@
+package tunnel
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+)
+
+const (
+	// The largest IP packet without jumbograms
+	MaxPacketSize = 65535
+
+	headerSize = 4
+)
+
+var (
+	errEmptyPacket    = errors.New("could not tunnel empty packet")
+	errPacketTooLarge = errors.New("could not tunnel packet larger than MaxPacketSize")
+)
+
+type TunConn struct {
+	conn net.Conn
+
+	readLock  sync.Mutex
+	writeLock sync.Mutex
+}
+
+func Dial(serverAddr string) (*TunConn, error) {
+	conn, err := tinynet.Dial("tcp", serverAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTunConn(conn), nil
+}
+
+func NewTunConn(conn net.Conn) *TunConn {
+	return &TunConn{
+		conn: conn,
+	}
+}
+
+// Each packet is sent with a 4-byte big-endian length prefix
+func (c *TunConn) WritePacket(ip []byte) error {
+	if len(ip) == 0 {
+		return errEmptyPacket
+	}
+
+	if len(ip) > MaxPacketSize {
+		return errPacketTooLarge
+	}
+
+	// Write header and packet in one buffer so that the packet is sent at once
+	msg := make([]byte, headerSize+len(ip))
+	binary.BigEndian.PutUint32(msg, uint32(len(ip)))
+	copy(msg[headerSize:], ip)
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	for len(msg) > 0 {
+		n, err := c.conn.Write(msg)
+		if err != nil {
+			return err
+		}
+
+		msg = msg[n:]
+	}
+
+	return nil
+}
+
+func (c *TunConn) ReadPacket() ([]byte, error) {
+	c.readLock.Lock()
+	defer c.readLock.Unlock()
+
+	// Read header
+	header := make([]byte, headerSize)
+	if _, err := tinynet.ReadFull(c.conn, header); err != nil {
+		return nil, err
+	}
+
+	// Don't let a misbehaving peer make us allocate arbitrary amounts of memory
+	length := binary.BigEndian.Uint32(header)
+	if length == 0 {
+		return nil, errEmptyPacket
+	}
+
+	if length > MaxPacketSize {
+		return nil, errPacketTooLarge
+	}
+
+	// Read packet
+	packet := make([]byte, length)
+	if _, err := tinynet.ReadFull(c.conn, packet); err != nil {
+		return nil, err
+	}
+
+	return packet, nil
+}
+
+func (c *TunConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *TunConn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+func (c *TunConn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+type TunListener struct {
+	l net.Listener
+}
+
+func Listen(addr string) (*TunListener, error) {
+	l, err := tinynet.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TunListener{
+		l: l,
+	}, nil
+}
+
+func (l *TunListener) Accept() (*TunConn, error) {
+	conn, err := l.l.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTunConn(conn), nil
+}
+
+func (l *TunListener) Close() error {
+	return l.l.Close()
+}
+
+func (l *TunListener) Addr() net.Addr {
+	return l.l.Addr()
+}
@@ -0,0 +1,152 @@
+package ws
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/alphahorizonio/tinynet/pkg/tinynet"
+	tinytls "github.com/alphahorizonio/tinynet/pkg/tls"
+)
+
+const (
+	acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+func acceptKey(key string) string {
+	hash := sha1.Sum([]byte(key + acceptGUID))
+
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func headerContainsToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// The request must have been read from conn without buffering any bytes past it
+func Upgrade(conn net.Conn, r *http.Request) (*WSConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+
+	if r.Method != http.MethodGet ||
+		!headerContainsToken(r.Header, "Connection", "upgrade") ||
+		!headerContainsToken(r.Header, "Upgrade", "websocket") ||
+		r.Header.Get("Sec-WebSocket-Version") != "13" ||
+		key == "" {
+		_, _ = io.WriteString(conn, "HTTP/1.1 400 Bad Request\r\nSec-WebSocket-Version: 13\r\nContent-Length: 0\r\nConnection: close\r\n\r\n")
+
+		return nil, errors.New("could not upgrade: invalid WebSocket handshake")
+	}
+
+	res := "HTTP/1.1 101 Switching Protocols\r\n"
+	res += "Upgrade: websocket\r\n"
+	res += "Connection: Upgrade\r\n"
+	res += "Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n"
+	res += "\r\n"
+
+	if _, err := io.WriteString(conn, res); err != nil {
+		return nil, err
+	}
+
+	return newWSConn(conn, bufio.NewReader(tinynet.NewEOFReader(conn)), false), nil
+}
+
+func Dial(rawURL string) (*WSConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	port := u.Port()
+	switch u.Scheme {
+	case "ws":
+		if port == "" {
+			port = "80"
+		}
+	case "wss":
+		if port == "" {
+			port = "443"
+		}
+	default:
+		return nil, fmt.Errorf("unsupported scheme %v", u.Scheme)
+	}
+
+	conn, err := tinynet.Dial("tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "wss" {
+		tlsConn, err := tinytls.UpgradeTLS(conn, &tls.Config{ServerName: u.Hostname()}, tinytls.RoleClient)
+		if err != nil {
+			_ = conn.Close()
+
+			return nil, err
+		}
+
+		conn = tlsConn
+	}
+
+	wsConn, err := handshake(conn, u)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return wsConn, nil
+}
+
+func handshake(conn net.Conn, u *url.URL) (*WSConn, error) {
+	rawKey := make([]byte, 16)
+	if _, err := rand.Read(rawKey); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(rawKey)
+
+	req := "GET " + u.RequestURI() + " HTTP/1.1\r\n"
+	req += "Host: " + u.Host + "\r\n"
+	req += "Upgrade: websocket\r\n"
+	req += "Connection: Upgrade\r\n"
+	req += "Sec-WebSocket-Key: " + key + "\r\n"
+	req += "Sec-WebSocket-Version: 13\r\n"
+	req += "\r\n"
+
+	if _, err := io.WriteString(conn, req); err != nil {
+		return nil, err
+	}
+
+	// Read response
+	reader := bufio.NewReader(tinynet.NewEOFReader(conn))
+	res, err := http.ReadResponse(reader, &http.Request{Method: http.MethodGet})
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("could not upgrade to WebSocket: %v", res.Status)
+	}
+
+	if !headerContainsToken(res.Header, "Upgrade", "websocket") || res.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		return nil, errors.New("could not upgrade to WebSocket: invalid handshake response")
+	}
+
+	// The reader may already hold the first frames
+	return newWSConn(conn, reader, true), nil
+}
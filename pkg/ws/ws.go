@@ -0,0 +1,332 @@
+package ws
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	OpContinuation = 0x0
+	OpText         = 0x1
+	OpBinary       = 0x2
+	OpClose        = 0x8
+	OpPing         = 0x9
+	OpPong         = 0xa
+)
+
+const (
+	CloseNormalClosure   = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseUnsupportedData = 1003
+	CloseNoStatus        = 1005
+	CloseInvalidPayload  = 1007
+	CloseMessageTooBig   = 1009
+)
+
+const (
+	DefaultMaxMessageSize = 1 << 24
+
+	maxControlPayloadSize = 125
+
+	finBit  = 0x80
+	rsvBits = 0x70
+	maskBit = 0x80
+)
+
+var (
+	errProtocol       = errors.New("could not read frame: protocol error")
+	errInvalidUTF8    = errors.New("could not read text message: invalid UTF-8")
+	errMessageTooBig  = errors.New("could not read message: message too big")
+	errControlTooLong = errors.New("could not write control frame: payload too long")
+)
+
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket closed with code %v: %v", e.Code, e.Reason)
+}
+
+type WSConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	// Clients mask the frames they send, servers require masked frames
+	client bool
+
+	closeSent bool
+	writeLock sync.Mutex
+}
+
+func newWSConn(conn net.Conn, reader *bufio.Reader, client bool) *WSConn {
+	return &WSConn{
+		conn:   conn,
+		reader: reader,
+		client: client,
+	}
+}
+
+func (c *WSConn) ReadMessage() (int, []byte, error) {
+	opcode := -1
+	data := []byte{}
+
+	for {
+		fin, frameOpcode, payload, err := c.readFrame()
+		if err != nil {
+			if err == errProtocol {
+				_ = c.writeClose(CloseProtocolError, "")
+			}
+
+			return 0, nil, err
+		}
+
+		switch frameOpcode {
+		case OpPing:
+			if err := c.WriteMessage(OpPong, payload); err != nil {
+				return 0, nil, err
+			}
+
+			continue
+		case OpPong:
+			continue
+		case OpClose:
+			code, reason := CloseNoStatus, ""
+			if len(payload) >= 2 {
+				code, reason = int(binary.BigEndian.Uint16(payload)), string(payload[2:])
+			}
+
+			// Echo the close frame to complete the closing handshake
+			if code == CloseNoStatus {
+				_ = c.writeClose(CloseNormalClosure, "")
+			} else {
+				_ = c.writeClose(code, "")
+			}
+
+			return 0, nil, &CloseError{code, reason}
+		case OpContinuation:
+			if opcode == -1 {
+				_ = c.writeClose(CloseProtocolError, "")
+
+				return 0, nil, errProtocol
+			}
+		case OpText, OpBinary:
+			if opcode != -1 {
+				_ = c.writeClose(CloseProtocolError, "")
+
+				return 0, nil, errProtocol
+			}
+
+			opcode = frameOpcode
+		default:
+			_ = c.writeClose(CloseProtocolError, "")
+
+			return 0, nil, errProtocol
+		}
+
+		if len(data)+len(payload) > DefaultMaxMessageSize {
+			_ = c.writeClose(CloseMessageTooBig, "")
+
+			return 0, nil, errMessageTooBig
+		}
+
+		data = append(data, payload...)
+
+		if !fin {
+			continue
+		}
+
+		if opcode == OpText && !utf8.Valid(data) {
+			_ = c.writeClose(CloseInvalidPayload, "")
+
+			return 0, nil, errInvalidUTF8
+		}
+
+		return opcode, data, nil
+	}
+}
+
+func (c *WSConn) WriteMessage(opcode int, data []byte) error {
+	if opcode == OpClose {
+		code, reason := CloseNormalClosure, ""
+		if len(data) >= 2 {
+			code, reason = int(binary.BigEndian.Uint16(data)), string(data[2:])
+		}
+
+		return c.writeClose(code, reason)
+	}
+
+	return c.writeFrame(opcode, data)
+}
+
+func (c *WSConn) Close() error {
+	_ = c.writeClose(CloseNormalClosure, "")
+
+	return c.conn.Close()
+}
+
+func (c *WSConn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+func (c *WSConn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+func (c *WSConn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+func (c *WSConn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+// Frame: FIN, RSV and opcode (1 byte), MASK and length (1 byte), extended length (0, 2 or 8 bytes), masking key (0 or 4 bytes), payload
+func (c *WSConn) readFrame() (bool, int, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&finBit != 0
+	opcode := int(header[0] & 0x0f)
+	masked := header[1]&maskBit != 0
+
+	// No extensions are negotiated, and only clients mask their frames
+	if header[0]&rsvBits != 0 || masked == c.client {
+		return false, 0, nil, errProtocol
+	}
+
+	length := uint64(header[1] &^ maskBit)
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		if _, err := io.ReadFull(c.reader, extended); err != nil {
+			return false, 0, nil, err
+		}
+
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		if _, err := io.ReadFull(c.reader, extended); err != nil {
+			return false, 0, nil, err
+		}
+
+		length = binary.BigEndian.Uint64(extended)
+	}
+
+	// Control frames can't be fragmented or carry large payloads
+	if opcode >= OpClose && (!fin || length > maxControlPayloadSize) {
+		return false, 0, nil, errProtocol
+	}
+
+	if length > DefaultMaxMessageSize {
+		_ = c.writeClose(CloseMessageTooBig, "")
+
+		return false, 0, nil, errMessageTooBig
+	}
+
+	key := make([]byte, 4)
+	if masked {
+		if _, err := io.ReadFull(c.reader, key); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	if masked {
+		maskBytes(key, payload)
+	}
+
+	return fin, opcode, payload, nil
+}
+
+func (c *WSConn) writeClose(code int, reason string) error {
+	c.writeLock.Lock()
+	closeSent := c.closeSent
+	c.closeSent = true
+	c.writeLock.Unlock()
+
+	// Only one close frame may be sent
+	if closeSent {
+		return nil
+	}
+
+	payload := make([]byte, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	copy(payload[2:], reason)
+
+	return c.writeFrame(OpClose, payload)
+}
+
+func (c *WSConn) writeFrame(opcode int, payload []byte) error {
+	if opcode >= OpClose && len(payload) > maxControlPayloadSize {
+		return errControlTooLong
+	}
+
+	frame := []byte{finBit | byte(opcode), 0}
+
+	switch {
+	case len(payload) < 126:
+		frame[1] = byte(len(payload))
+	case len(payload) <= 0xffff:
+		frame[1] = 126
+		frame = append(frame, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(len(payload)))
+	default:
+		frame[1] = 127
+		frame = append(frame, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(frame[2:], uint64(len(payload)))
+	}
+
+	if c.client {
+		frame[1] |= maskBit
+
+		key := make([]byte, 4)
+		if _, err := rand.Read(key); err != nil {
+			return err
+		}
+
+		frame = append(frame, key...)
+
+		start := len(frame)
+		frame = append(frame, payload...)
+		maskBytes(key, frame[start:])
+	} else {
+		frame = append(frame, payload...)
+	}
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	for len(frame) > 0 {
+		n, err := c.conn.Write(frame)
+		if err != nil {
+			return err
+		}
+
+		frame = frame[n:]
+	}
+
+	return nil
+}
+
+func maskBytes(key []byte, b []byte) {
+	for i := range b {
+		b[i] ^= key[i%4]
+	}
+}